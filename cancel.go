@@ -0,0 +1,151 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrCanceled is the Definition of the errors returned by FromContext for canceled contexts. Like
+// ErrDeadlineExceeded, it is not registered, so its code is neither reserved nor exported by Catalog.
+var ErrCanceled = newDefinition("CONTEXT_CANCELED", Canceled, "context canceled")
+
+// ErrDeadlineExceeded is the Definition of the errors returned by FromContext for contexts whose
+// deadline has passed.
+var ErrDeadlineExceeded = newDefinition("CONTEXT_DEADLINE_EXCEEDED", DeadlineExceeded, "context deadline exceeded")
+
+// builtinDefinitions are the Definitions of the package, found by lookup but kept out of the registry.
+var builtinDefinitions = map[string]*Definition{
+	ErrCanceled.code:         ErrCanceled,
+	ErrDeadlineExceeded.code: ErrDeadlineExceeded,
+}
+
+// CancelFunc cancels a context created by CancelWithDetail or TimeoutWithDetail. The arguments are
+// composed into the message of the cancellation cause, a *Detail that records the caller of the
+// CancelFunc and its debug stack. Only the first call has effect.
+type CancelFunc func(args ...any)
+
+type startKey struct{}
+
+// FromContext returns the error of a done context as a *Detail, classified by ErrCanceled or
+// ErrDeadlineExceeded. The cause of the cancellation, as returned by context.Cause, is kept as the
+// wrapped error, so the message tells why the context was canceled, while errors.Is still matches the
+// error of the context, context.Canceled or context.DeadlineExceeded. The deadline of the context is
+// recorded as the "deadline" field and, for a context created by CancelWithDetail or TimeoutWithDetail,
+// the time from its creation until its deadline passed or its CancelFunc was called is recorded as the
+// "elapsed" field, regardless of when FromContext is called.
+//
+// Parameters:
+//   - ctx: The context to be checked.
+//
+// Returns:
+//   - error: A *Detail describing why ctx is done, or nil when ctx is not done.
+//
+// Example:
+//
+//	ctx, cancel := CancelWithDetail(context.Background())
+//	cancel("shutting down")
+//
+//	err := FromContext(ctx)
+//	fmt.Println(Details(err).Message()) // context canceled: shutting down
+//	fmt.Println(ErrCanceled.Is(err))    // true
+func FromContext(ctx context.Context) error {
+	ctxErr := ctx.Err()
+	if ctxErr == nil {
+		return nil
+	}
+
+	def := ErrCanceled
+	if errors.Is(ctxErr, context.DeadlineExceeded) {
+		def = ErrDeadlineExceeded
+	}
+
+	detail := def.newDetail(1)
+	detail.ctxErr = ctxErr
+	if cause := context.Cause(ctx); cause != nil && cause != ctxErr {
+		detail.cause = cause
+	} else {
+		detail.message = ""
+		detail.cause = ctxErr
+	}
+
+	detail.fields = Fields{}
+	if deadline, ok := ctx.Deadline(); ok {
+		detail.fields["deadline"] = deadline
+	}
+	if start, ok := ctx.Value(startKey{}).(time.Time); ok {
+		if end, ok := doneTime(ctx, def); ok {
+			detail.fields["elapsed"] = end.Sub(start)
+		}
+	}
+	return detail
+}
+
+// Is reports whether target is the error of the context a *Detail was built from by FromContext, so
+// errors.Is matches context.Canceled and context.DeadlineExceeded when the cause of the cancellation is
+// wrapped in its place.
+func (e *Detail) Is(target error) bool {
+	return e.ctxErr != nil && target == e.ctxErr
+}
+
+// doneTime returns when ctx was done: its deadline when it was exceeded, or the time the cause of the
+// cancellation was created by a CancelFunc. It reports false when the time is unknown, such as for a
+// context canceled by its parent.
+func doneTime(ctx context.Context, def *Definition) (time.Time, bool) {
+	if deadline, ok := ctx.Deadline(); ok && def == ErrDeadlineExceeded {
+		return deadline, true
+	}
+	if cause, ok := context.Cause(ctx).(*Detail); ok && cause.kind == Canceled {
+		return cause.time, true
+	}
+	return time.Time{}, false
+}
+
+// CancelWithDetail works like context.WithCancelCause, but the returned CancelFunc builds the
+// cancellation cause as a *Detail, capturing where and when the context was canceled. The creation time
+// of the context is recorded, so FromContext reports the time elapsed until the cancellation.
+//
+// Parameters:
+//   - ctx: The parent context.
+//
+// Returns:
+//   - context.Context: The cancelable context.
+//   - CancelFunc: The function that cancels the context with a detailed cause.
+func CancelWithDetail(ctx context.Context) (context.Context, CancelFunc) {
+	ctx = context.WithValue(ctx, startKey{}, time.Now())
+	ctx, cancel := context.WithCancelCause(ctx)
+	return ctx, func(args ...any) {
+		cancel(newCancelCause(args...))
+	}
+}
+
+// TimeoutWithDetail works like CancelWithDetail, and the context is also canceled once the timeout
+// elapses. In that case the cause is a *Detail that points to the caller of TimeoutWithDetail, so the
+// error tells where the exceeded deadline was set.
+//
+// Parameters:
+//   - ctx: The parent context.
+//   - timeout: The maximum duration of the context.
+//
+// Returns:
+//   - context.Context: The context with the timeout.
+//   - CancelFunc: The function that cancels the context with a detailed cause and releases its resources.
+func TimeoutWithDetail(ctx context.Context, timeout time.Duration) (context.Context, CancelFunc) {
+	timeoutCause := newDetail(1, "timeout of "+timeout.String()+" exceeded")
+	timeoutCause.kind = DeadlineExceeded
+
+	ctx = context.WithValue(ctx, startKey{}, time.Now())
+	ctx, cancelTimeout := context.WithTimeoutCause(ctx, timeout, timeoutCause)
+	ctx, cancel := context.WithCancelCause(ctx)
+	return ctx, func(args ...any) {
+		cancel(newCancelCause(args...))
+		cancelTimeout()
+	}
+}
+
+// newCancelCause builds the cause given by a CancelFunc, taking the caller of the CancelFunc as origin.
+func newCancelCause(args ...any) *Detail {
+	detail := newDetail(2, buildMessage(args...))
+	detail.kind = Canceled
+	return detail
+}
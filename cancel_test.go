@@ -0,0 +1,132 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFromContext(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	withCause, cancelCause := context.WithCancelCause(context.Background())
+	cancelCause(errors.New("shutting down"))
+
+	expiredWithCause, cancelExpiredCause := context.WithDeadlineCause(context.Background(),
+		time.Now().Add(-time.Second), errors.New("batch too slow"))
+	defer cancelExpiredCause()
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		wantDef     *Definition
+		wantMessage string
+		wantIs      error
+	}{
+		{"Context is canceled", canceled, ErrCanceled, "context canceled", context.Canceled},
+		{"Context deadline is exceeded", expired, ErrDeadlineExceeded, "context deadline exceeded", context.DeadlineExceeded},
+		{"Context is canceled with cause", withCause, ErrCanceled, "context canceled: shutting down", context.Canceled},
+		{"Context deadline is exceeded with cause", expiredWithCause, ErrDeadlineExceeded, "context deadline exceeded: batch too slow", context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromContext(tt.ctx)
+			detail := Details(err)
+			if !tt.wantDef.Is(err) || detail.Kind() != tt.wantDef.Kind() {
+				t.Errorf("FromContext() code = %v, kind = %v", detail.Code(), detail.Kind())
+			}
+			if detail.Message() != tt.wantMessage {
+				t.Errorf("FromContext() message = %v, want %v", detail.Message(), tt.wantMessage)
+			}
+			if !errors.Is(err, tt.wantIs) || !errors.Is(Wrap(err, "running batch"), tt.wantIs) {
+				t.Errorf("FromContext() should match %v", tt.wantIs)
+			}
+		})
+	}
+}
+
+func TestFromContextNotDone(t *testing.T) {
+	if err := FromContext(context.Background()); err != nil {
+		t.Errorf("FromContext() = %v, want nil", err)
+	}
+}
+
+func TestCancelWithDetail(t *testing.T) {
+	ctx, cancel := CancelWithDetail(context.Background())
+	cancel("shutting", "down")
+
+	err := FromContext(ctx)
+	detail := Details(err)
+	if detail.Message() != "context canceled: shutting down" {
+		t.Errorf("FromContext() message = %v", detail.Message())
+	}
+	elapsed, ok := detail.Fields()["elapsed"].(time.Duration)
+	if !ok {
+		t.Errorf("FromContext() should record the elapsed time, got %v", detail.Fields())
+	}
+	time.Sleep(10 * time.Millisecond)
+	if later := Details(FromContext(ctx)).Fields()["elapsed"]; later != elapsed {
+		t.Errorf("FromContext() elapsed = %v later, want %v measured at the cancellation", later, elapsed)
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Error("FromContext() should match context.Canceled")
+	}
+
+	var cause *Detail
+	if !errors.As(detail.Unwrap(), &cause) || cause.Func() != "TestCancelWithDetail" {
+		t.Errorf("CancelFunc should record its caller, got %v", detail.Unwrap())
+	}
+}
+
+func TestTimeoutWithDetail(t *testing.T) {
+	ctx, cancel := TimeoutWithDetail(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	time.Sleep(50 * time.Millisecond)
+
+	err := FromContext(ctx)
+	detail := Details(err)
+	if !ErrDeadlineExceeded.Is(err) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("FromContext() code = %v, want DEADLINE_EXCEEDED", detail.Code())
+	}
+	if detail.Message() != "context deadline exceeded: timeout of 1ms exceeded" {
+		t.Errorf("FromContext() message = %v", detail.Message())
+	}
+	if _, ok := detail.Fields()["deadline"].(time.Time); !ok {
+		t.Errorf("FromContext() should record the deadline, got %v", detail.Fields())
+	}
+	if elapsed, _ := detail.Fields()["elapsed"].(time.Duration); elapsed < time.Millisecond || elapsed >= 50*time.Millisecond {
+		t.Errorf("FromContext() elapsed = %v, want the timeout measured up to the deadline", elapsed)
+	}
+
+	var cause *Detail
+	if !errors.As(detail.Unwrap(), &cause) || cause.Func() != "TestTimeoutWithDetail" {
+		t.Errorf("timeout cause should record the caller of TimeoutWithDetail, got %v", detail.Unwrap())
+	}
+}
+
+func TestContextDefinitionsNotRegistered(t *testing.T) {
+	for _, def := range []*Definition{ErrCanceled, ErrDeadlineExceeded} {
+		if _, ok := Lookup(def.Code()); ok {
+			t.Errorf("Lookup(%q) should not find the definitions of the package", def.Code())
+		}
+		for _, entry := range Catalog() {
+			if entry.Code == def.Code() {
+				t.Errorf("Catalog() should not export %q", def.Code())
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if problem := ToProblem(FromContext(ctx)); problem.Code != ErrCanceled.Code() {
+		t.Errorf("ToProblem() code = %v, want %v", problem.Code, ErrCanceled.Code())
+	}
+}
@@ -0,0 +1,272 @@
+package errors
+
+import (
+	"debug/elf"
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// StackCapture defines how the errors of the package capture the stack of the goroutine that creates them.
+type StackCapture int32
+
+const (
+	// StackFull captures the debug stack, as printed by runtime/debug.Stack. It is the default mode.
+	StackFull StackCapture = iota
+	// StackPCs captures only the program counters of the stack, which is several times faster and
+	// produces small payloads. The frames are resolved in process by Detail.Frames, while serialized
+	// errors carry the PCStack, which can be symbolized offline against the binary that produced them.
+	StackPCs
+	// StackOff captures no stack, for the hot paths where the origin of the error is enough.
+	StackOff
+)
+
+// pcStackPrefix identifies the compact representation of a PCStack kept as the stack of the error.
+const pcStackPrefix = "pcs"
+
+// pcStackDepth is the maximum number of program counters captured by StackPCs, unless the stack depth
+// is configured.
+const pcStackDepth = 64
+
+// SetStackCapture sets how the errors created from now on capture their stack, which is the same as
+// calling Configure with ConfigStack. It is safe to be called concurrently, but it is usually called once
+// during the program initialization.
+//
+// Parameters:
+//   - mode: The StackCapture mode.
+//
+// Example:
+//
+//	func main() {
+//		errors.SetStackCapture(errors.StackPCs)
+//		// ...
+//	}
+func SetStackCapture(mode StackCapture) {
+	Configure(ConfigStack(mode))
+}
+
+// PCStack is a stack captured as raw program counters, along with the information needed to symbolize
+// it offline: the Go build ID of the binary and the address of a known function of the package, which
+// reveals the offset where a position independent binary was loaded.
+type PCStack struct {
+	// BuildID is the Go build ID of the binary, empty when it could not be read.
+	BuildID string
+	// Anchor is the runtime address of the pcAnchor function of the package.
+	Anchor uintptr
+	// PCs are the return program counters of the stack, from the innermost call to the outermost one.
+	PCs []uintptr
+}
+
+// PCAnchorSymbol is the symbol of the function whose address is recorded as the PCStack anchor.
+const PCAnchorSymbol = "github.com/tech4works/errors.pcAnchor"
+
+// String returns the compact representation of the stack, e.g. "pcs build=abc anchor=0x4a10 0x4b2c 0x4c00",
+// which is the stack printed by Detail.Error for errors captured with StackPCs.
+func (s PCStack) String() string {
+	var sb strings.Builder
+	sb.WriteString(pcStackPrefix)
+	if s.BuildID != "" {
+		sb.WriteString(" build=" + s.BuildID)
+	}
+	sb.WriteString(" anchor=" + formatPC(s.Anchor))
+	for _, pc := range s.PCs {
+		sb.WriteString(" " + formatPC(pc))
+	}
+	return sb.String()
+}
+
+// ParsePCStack parses the compact representation returned by PCStack.String.
+//
+// Parameters:
+//   - s: The compact representation of the stack.
+//
+// Returns:
+//   - PCStack: The parsed stack.
+//   - bool: A boolean value indicating whether s is a valid PCStack representation.
+func ParsePCStack(s string) (PCStack, bool) {
+	words := strings.Fields(s)
+	if len(words) == 0 || words[0] != pcStackPrefix {
+		return PCStack{}, false
+	}
+
+	var stack PCStack
+	for _, word := range words[1:] {
+		switch {
+		case strings.HasPrefix(word, "build="):
+			stack.BuildID = strings.TrimPrefix(word, "build=")
+		case strings.HasPrefix(word, "anchor="):
+			anchor, ok := parsePC(strings.TrimPrefix(word, "anchor="))
+			if !ok {
+				return PCStack{}, false
+			}
+			stack.Anchor = anchor
+		default:
+			pc, ok := parsePC(word)
+			if !ok {
+				return PCStack{}, false
+			}
+			stack.PCs = append(stack.PCs, pc)
+		}
+	}
+	return stack, true
+}
+
+// PCStack returns the program counters captured for the error when it was created with the StackPCs
+// mode, either in this process or in the process that serialized it.
+//
+// Returns:
+//   - PCStack: The captured stack.
+//   - bool: A boolean value indicating whether the error carries a PCStack.
+func (e *Detail) PCStack() (PCStack, bool) {
+	return ParsePCStack(e.stack)
+}
+
+// captureStack returns the stack of the error, as a debug stack or as the compact PCStack representation
+// according to the StackCapture mode and limited to the configured depth, and the program counters,
+// which are only captured by StackPCs. The skip value is the one given to runtime.Callers by the caller
+// of captureStack.
+func captureStack(skip int) (string, []uintptr) {
+	c := loadConfig()
+	switch c.Stack {
+	case StackOff:
+		return "", nil
+	case StackPCs:
+		depth := pcStackDepth
+		if c.StackDepth > 0 {
+			depth = c.StackDepth
+		}
+		pcs := make([]uintptr, depth)
+		pcs = pcs[:runtime.Callers(skip+1, pcs)]
+		stack := PCStack{BuildID: BuildID(), Anchor: reflect.ValueOf(pcAnchor).Pointer(), PCs: pcs}
+		return stack.String(), pcs
+	default:
+		stack := string(debug.Stack())
+		if c.StackDepth > 0 {
+			// The frames of the debug stack start at runtime/debug.Stack, which takes the place of
+			// runtime.Callers, so the origin is at the same index.
+			stack = truncateStack(stack, skip+1+c.StackDepth)
+		}
+		return stack, nil
+	}
+}
+
+// truncateStack keeps the goroutine header, the first frames and the "created by" frame of a debug stack,
+// marking the elided frames as the runtime does.
+func truncateStack(stack string, frames int) string {
+	lines := strings.Split(stack, "\n")
+	kept := make([]string, 0, len(lines))
+	elided := false
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if line == "" || strings.HasPrefix(line, "goroutine ") || strings.HasPrefix(line, "\t") {
+			if line == "" || !strings.HasPrefix(line, "\t") {
+				kept = append(kept, line)
+			}
+			continue
+		}
+
+		created := strings.HasPrefix(line, "created by ")
+		keep := created || frames > 0
+		if !created {
+			frames--
+		}
+		if !keep && !elided {
+			kept = append(kept, "...additional frames elided...")
+			elided = true
+		}
+		if keep {
+			kept = append(kept, line)
+		}
+		if i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\t") {
+			if keep {
+				kept = append(kept, lines[i+1])
+			}
+			i++
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// pcFrames resolves the frames of program counters captured in this process.
+func pcFrames(pcs []uintptr) []Frame {
+	var frames []Frame
+	callers := runtime.CallersFrames(pcs)
+	for {
+		frame, more := callers.Next()
+		if frame.Function != "" {
+			frames = append(frames, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		}
+		if !more {
+			return frames
+		}
+	}
+}
+
+// pcAnchor is never called, its address is recorded in every PCStack.
+func pcAnchor() {}
+
+var buildID = sync.OnceValue(func() string {
+	path, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	id, _ := ReadBuildID(path)
+	return id
+})
+
+// BuildID returns the Go build ID of the running binary, as printed by "go tool buildid", or an empty
+// string when it cannot be read, e.g. when the binary is not in the ELF format.
+func BuildID() string {
+	return buildID()
+}
+
+// ReadBuildID reads the Go build ID of an ELF binary, from its ".note.go.buildid" section.
+//
+// Parameters:
+//   - path: The path of the binary.
+//
+// Returns:
+//   - string: The Go build ID.
+//   - error: An error if the binary could not be read or has no Go build ID.
+func ReadBuildID(path string) (string, error) {
+	file, err := elf.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	section := file.Section(".note.go.buildid")
+	if section == nil {
+		return "", fmt.Errorf("errors: %s has no Go build ID", path)
+	}
+	note, err := section.Data()
+	if err != nil {
+		return "", err
+	}
+	// The note holds the name size, the description size and the type, followed by the "Go" name padded
+	// to 4 bytes and by the build ID as the description.
+	if len(note) < 16 {
+		return "", fmt.Errorf("errors: invalid Go build ID note in %s", path)
+	}
+	nameSize := file.ByteOrder.Uint32(note[0:4])
+	descSize := file.ByteOrder.Uint32(note[4:8])
+	start := 12 + (nameSize+3)&^3
+	if uint64(start)+uint64(descSize) > uint64(len(note)) {
+		return "", fmt.Errorf("errors: invalid Go build ID note in %s", path)
+	}
+	return string(note[start : start+descSize]), nil
+}
+
+func formatPC(pc uintptr) string {
+	return "0x" + strconv.FormatUint(uint64(pc), 16)
+}
+
+func parsePC(s string) (uintptr, bool) {
+	pc, err := strconv.ParseUint(strings.TrimPrefix(s, "0x"), 16, 64)
+	return uintptr(pc), err == nil
+}
@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"encoding/json"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSetStackCapture(t *testing.T) {
+	SetStackCapture(StackPCs)
+	t.Cleanup(func() { SetStackCapture(StackFull) })
+
+	detail := Details(New("test error"))
+	stack, ok := detail.PCStack()
+	if !ok || len(stack.PCs) == 0 || stack.Anchor == 0 {
+		t.Fatalf("Detail.PCStack() = %+v, %v", stack, ok)
+	}
+	if !strings.Contains(detail.Error(), "/capture_test.go:") || !IsDetailed(detail) {
+		t.Errorf("Detail.Error() = %v", detail.Error())
+	}
+	if runtime.GOOS == "linux" && stack.BuildID == "" {
+		t.Error("Detail.PCStack() has no build ID")
+	}
+
+	frames := detail.Frames()
+	if len(frames) == 0 || frames[0].Function != "github.com/tech4works/errors.TestSetStackCapture" ||
+		frames[0].Line != detail.Line() {
+		t.Errorf("Detail.Frames() = %v", frames)
+	}
+	if frames := Details(Wrap(detail, "wrapped")).Frames(); len(frames) == 0 || frames[0].Line == detail.Line() {
+		t.Errorf("Wrap() Frames() = %v", frames)
+	}
+}
+
+func TestPCStackJSON(t *testing.T) {
+	SetStackCapture(StackPCs)
+	t.Cleanup(func() { SetStackCapture(StackFull) })
+
+	detail := Details(New("test error"))
+	bs, err := json.Marshal(detail)
+	if err != nil {
+		t.Fatalf("Detail.MarshalJSON() error = %v", err)
+	}
+	if strings.Contains(string(bs), `"stack"`) || !strings.Contains(string(bs), `"pc_stack":{`) {
+		t.Errorf("Detail.MarshalJSON() = %s", bs)
+	}
+
+	var decoded Detail
+	if err = json.Unmarshal(bs, &decoded); err != nil {
+		t.Fatalf("Detail.UnmarshalJSON() error = %v", err)
+	}
+	want, _ := detail.PCStack()
+	got, ok := decoded.PCStack()
+	if !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("decoded PCStack() = %+v, want %+v", got, want)
+	}
+	if frames := decoded.Frames(); len(frames) != 0 {
+		t.Errorf("decoded Frames() = %v, want no frames", frames)
+	}
+}
+
+func TestParsePCStack(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   PCStack
+		wantOk bool
+	}{
+		{"Complete stack", "pcs build=abc/def anchor=0x10 0x20 0x3f", PCStack{BuildID: "abc/def", Anchor: 0x10, PCs: []uintptr{0x20, 0x3f}}, true},
+		{"Without build ID", "pcs anchor=0x10 0x20", PCStack{Anchor: 0x10, PCs: []uintptr{0x20}}, true},
+		{"Debug stack", "goroutine 1 [running]:", PCStack{}, false},
+		{"Invalid PC", "pcs anchor=0x10 zz", PCStack{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParsePCStack(tt.input)
+			if ok != tt.wantOk || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePCStack() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOk)
+			}
+			if ok && got.String() != tt.input {
+				t.Errorf("PCStack.String() = %v, want %v", got.String(), tt.input)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"os"
+	"regexp"
+	"slices"
+
+	"github.com/tech4works/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// catalog is the file read by errgen, written in YAML or JSON.
+type catalog struct {
+	Package string       `yaml:"package" json:"package"`
+	Errors  []definition `yaml:"errors" json:"errors"`
+}
+
+type definition struct {
+	Code          string   `yaml:"code" json:"code"`
+	Kind          string   `yaml:"kind" json:"kind"`
+	HTTPStatus    int      `yaml:"http_status" json:"http_status"`
+	Message       string   `yaml:"message" json:"message"`
+	Params        []param  `yaml:"params" json:"params"`
+	PublicMessage string   `yaml:"public_message" json:"public_message"`
+	DocsURL       string   `yaml:"docs_url" json:"docs_url"`
+	Hints         []string `yaml:"hints" json:"hints"`
+	Description   string   `yaml:"description" json:"description"`
+}
+
+type param struct {
+	Name string `yaml:"name" json:"name"`
+	Type string `yaml:"type" json:"type"`
+}
+
+// kinds maps the kinds accepted in the catalog to the constants of the errors package.
+var kinds = map[string]string{
+	"UNKNOWN":             "Unknown",
+	"CANCELED":            "Canceled",
+	"INVALID_ARGUMENT":    "InvalidArgument",
+	"DEADLINE_EXCEEDED":   "DeadlineExceeded",
+	"NOT_FOUND":           "NotFound",
+	"ALREADY_EXISTS":      "AlreadyExists",
+	"PERMISSION_DENIED":   "PermissionDenied",
+	"RESOURCE_EXHAUSTED":  "ResourceExhausted",
+	"FAILED_PRECONDITION": "FailedPrecondition",
+	"ABORTED":             "Aborted",
+	"OUT_OF_RANGE":        "OutOfRange",
+	"UNIMPLEMENTED":       "Unimplemented",
+	"INTERNAL":            "Internal",
+	"UNAVAILABLE":         "Unavailable",
+	"DATA_LOSS":           "DataLoss",
+	"UNAUTHENTICATED":     "Unauthenticated",
+}
+
+// paramTypes lists the Go types accepted for the params of a definition.
+var paramTypes = []string{
+	"string", "bool", "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32",
+	"uint64", "float32", "float64", "any",
+}
+
+var placeholderRegex = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_.]*)}`)
+
+func readCatalog(path string) (*catalog, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c catalog
+	if err = yaml.Unmarshal(bs, &c); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &c, c.validate()
+}
+
+func (c *catalog) validate() error {
+	seen := map[string]bool{}
+	for i, def := range c.Errors {
+		if def.Code == "" {
+			return fmt.Errorf("errors[%d]: code is required", i)
+		}
+		if seen[def.Code] {
+			return fmt.Errorf("%s: duplicate code", def.Code)
+		}
+		seen[def.Code] = true
+
+		if def.Kind == "" {
+			c.Errors[i].Kind = "UNKNOWN"
+		} else if _, ok := kinds[def.Kind]; !ok {
+			return fmt.Errorf("%s: unknown kind %q", def.Code, def.Kind)
+		}
+		if err := def.validateParams(); err != nil {
+			return fmt.Errorf("%s: %w", def.Code, err)
+		}
+	}
+	return nil
+}
+
+func (d definition) validateParams() error {
+	var names []string
+	for _, p := range d.Params {
+		if !token.IsIdentifier(p.Name) || token.IsKeyword(p.Name) {
+			return fmt.Errorf("param %q is not a valid Go identifier", p.Name)
+		}
+		// The params are the arguments of the generated constructor, so they must not shadow the
+		// identifiers used in its body or in the parameter types.
+		if p.Name == "errors" || p.Name == "Err"+d.goName() || types.Universe.Lookup(p.Name) != nil {
+			return fmt.Errorf("param %q collides with an identifier of the generated code", p.Name)
+		}
+		if !slices.Contains(paramTypes, p.Type) {
+			return fmt.Errorf("param %q has unsupported type %q", p.Name, p.Type)
+		}
+		if slices.Contains(names, p.Name) {
+			return fmt.Errorf("param %q is declared twice", p.Name)
+		}
+		names = append(names, p.Name)
+	}
+
+	var placeholders []string
+	for _, match := range placeholderRegex.FindAllStringSubmatch(d.Message, -1) {
+		if !slices.Contains(names, match[1]) {
+			return fmt.Errorf("placeholder {%s} is not declared in params", match[1])
+		}
+		placeholders = append(placeholders, match[1])
+	}
+	for _, name := range names {
+		if !slices.Contains(placeholders, name) {
+			return fmt.Errorf("param %q is not used in the message", name)
+		}
+	}
+	return nil
+}
+
+// entries converts the catalog to the entries exported by the errors package.
+func (c *catalog) entries() []errors.CatalogEntry {
+	entries := make([]errors.CatalogEntry, 0, len(c.Errors))
+	for _, def := range c.Errors {
+		entry := errors.CatalogEntry{
+			Code:          def.Code,
+			Kind:          errors.Kind(def.Kind),
+			Template:      def.Message,
+			DocsURL:       def.DocsURL,
+			HTTPStatus:    def.HTTPStatus,
+			PublicMessage: def.PublicMessage,
+			Hints:         def.Hints,
+		}
+		if entry.HTTPStatus == 0 {
+			entry.HTTPStatus = entry.Kind.HTTPStatus()
+		}
+		for _, p := range def.Params {
+			if entry.ParamTypes == nil {
+				entry.ParamTypes = map[string]string{}
+			}
+			entry.Params = append(entry.Params, p.Name)
+			entry.ParamTypes[p.Name] = p.Type
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// goName converts a code such as "USER_NOT_FOUND" to "UserNotFound".
+func (d definition) goName() string {
+	return errors.CatalogEntry{Code: d.Code}.Name()
+}
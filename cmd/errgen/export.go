@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"text/template"
+)
+
+var exportTemplate = template.Must(template.New("export").Parse(`// Code generated by errgen. DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/tech4works/errors"
+
+	_ {{ printf "%q" .Import }}
+)
+
+func main() {
+	if err := errors.{{ .Writer }}(os.Stdout, errors.Catalog()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`))
+
+// exportPackage exports the definitions that the package at the given import path registers with
+// errors.Define. Those only exist at runtime, so a temporary program that imports the package and writes
+// errors.Catalog() is run with "go run". The program is created in the current directory, so the import
+// path is resolved by the module that contains it.
+func exportPackage(importPath, lang string) ([]byte, error) {
+	var writer string
+	switch lang {
+	case "openapi":
+		writer = "WriteOpenAPI"
+	case "typescript":
+		writer = "WriteTypeScript"
+	default:
+		return nil, fmt.Errorf("unsupported language %q with -from, use openapi or typescript", lang)
+	}
+
+	dir, err := os.MkdirTemp(".", ".errgen-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	var src bytes.Buffer
+	if err := exportTemplate.Execute(&src, struct{ Import, Writer string }{importPath, writer}); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), src.Bytes(), 0o644); err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", "run", "./"+filepath.Base(dir))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("exporting %s: %w\n%s", importPath, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/tech4works/errors"
+)
+
+var sourceTemplate = template.Must(template.New("source").Funcs(template.FuncMap{
+	"quote": strconv.Quote,
+	"kind":  func(k string) string { return kinds[k] },
+	"comment": func(s string) string {
+		return strings.ReplaceAll(strings.TrimSpace(s), "\n", "\n// ")
+	},
+}).Parse(`// Code generated by errgen from {{ .Source }}. DO NOT EDIT.
+
+package {{ .Package }}
+
+import "github.com/tech4works/errors"
+
+var (
+{{- range $i, $e := .Errors }}
+{{- if $i }}
+{{ end }}
+	// Err{{ .GoName }} is the definition of the {{ .Code }} error.
+	{{- with .Description }}
+	// {{ comment . }}
+	{{- end }}
+	Err{{ .GoName }} = errors.Define({{ quote .Code }}, errors.{{ kind .Kind }}, {{ quote .Message }},
+		errors.Params({{ range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ quote $p.Name }}{{ end }}),
+		{{- range .Params }}
+		errors.ParamType({{ quote .Name }}, {{ quote .Type }}),
+		{{- end }}
+		{{- if .HTTPStatus }}
+		errors.HTTPStatus({{ .HTTPStatus }}),
+		{{- end }}
+		{{- with .PublicMessage }}
+		errors.PublicMessage({{ quote . }}),
+		{{- end }}
+		{{- with .DocsURL }}
+		errors.DocsURL({{ quote . }}),
+		{{- end }}
+		{{- range .Hints }}
+		errors.Hint({{ quote . }}),
+		{{- end }}
+	)
+{{- end }}
+)
+{{ range .Errors }}
+// New{{ .GoName }} creates a new {{ .Code }} error, capturing the caller of New{{ .GoName }}.
+func New{{ .GoName }}({{ range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ $p.Name }} {{ $p.Type }}{{ end }}) error {
+	return Err{{ .GoName }}.NewSkipCaller(2, errors.Fields{
+		{{- range .Params }}
+		{{ quote .Name }}: {{ .Name }},
+		{{- end }}
+	})
+}
+
+// Is{{ .GoName }} reports whether err, or any error wrapped by it, was created from Err{{ .GoName }}.
+func Is{{ .GoName }}(err error) bool {
+	return Err{{ .GoName }}.Is(err)
+}
+{{ end }}`))
+
+type sourceData struct {
+	Source  string
+	Package string
+	Errors  []sourceDefinition
+}
+
+type sourceDefinition struct {
+	definition
+	GoName string
+}
+
+// generate renders the Go source with the sentinel definitions, constructors and Is helpers of the
+// catalog.
+func generate(c *catalog, source string) ([]byte, error) {
+	data := sourceData{Source: source, Package: c.Package}
+	for _, def := range c.Errors {
+		data.Errors = append(data.Errors, sourceDefinition{definition: def, GoName: def.goName()})
+	}
+
+	var buf bytes.Buffer
+	if err := sourceTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	bs, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated source: %w", err)
+	}
+	return bs, nil
+}
+
+// generateOpenAPI renders the OpenAPI components of the catalog.
+func generateOpenAPI(c *catalog) ([]byte, error) {
+	var buf bytes.Buffer
+	err := errors.WriteOpenAPI(&buf, c.entries())
+	return buf.Bytes(), err
+}
+
+// generateTypeScript renders the TypeScript module of the catalog.
+func generateTypeScript(c *catalog) ([]byte, error) {
+	var buf bytes.Buffer
+	err := errors.WriteTypeScript(&buf, c.entries())
+	return buf.Bytes(), err
+}
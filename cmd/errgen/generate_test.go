@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	c, err := readCatalog("testdata/catalog.yaml")
+	if err != nil {
+		t.Fatalf("readCatalog() error = %v", err)
+	}
+
+	got, err := generate(c, "catalog.yaml")
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+
+	want, err := os.ReadFile("testdata/catalog_gen.go.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("generate() output differs from testdata/catalog_gen.go.golden:\n%s", got)
+	}
+}
+
+func TestCatalog_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		def     definition
+		wantErr string
+	}{
+		{"Code is missing", definition{Message: "failed"}, "code is required"},
+		{"Kind is unknown", definition{Code: "A", Kind: "BROKEN"}, "unknown kind"},
+		{"Placeholder not declared", definition{Code: "A", Message: "user {id}"}, "not declared"},
+		{"Param not used", definition{Code: "A", Message: "user", Params: []param{{"id", "string"}}}, "not used"},
+		{"Param type unsupported", definition{Code: "A", Message: "{id}", Params: []param{{"id", "chan int"}}}, "unsupported type"},
+		{"Param name is keyword", definition{Code: "A", Message: "{type}", Params: []param{{"type", "string"}}}, "valid Go identifier"},
+		{"Param name is the errors import", definition{Code: "A", Message: "{errors}", Params: []param{{"errors", "int"}}}, "collides"},
+		{"Param name is predeclared", definition{Code: "A", Message: "{string}", Params: []param{{"string", "string"}}}, "collides"},
+		{"Param name is the definition", definition{Code: "USER", Message: "{ErrUser}", Params: []param{{"ErrUser", "int"}}}, "collides"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &catalog{Package: "apperrors", Errors: []definition{tt.def}}
+			if err := c.validate(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("catalog.validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCatalog_ValidateDuplicate(t *testing.T) {
+	c := &catalog{Errors: []definition{{Code: "A"}, {Code: "A"}}}
+	if err := c.validate(); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Errorf("catalog.validate() error = %v, want duplicate code", err)
+	}
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	c, err := readCatalog("testdata/catalog.yaml")
+	if err != nil {
+		t.Fatalf("readCatalog() error = %v", err)
+	}
+
+	got, err := generateTypeScript(c)
+	if err != nil {
+		t.Fatalf("generateTypeScript() error = %v", err)
+	}
+	want := `export type OrderLimitExceededProblem = Problem<"ORDER_LIMIT_EXCEEDED", { "orderID": string; "limit": number }>;`
+	if !strings.Contains(string(got), want) {
+		t.Errorf("generateTypeScript() should contain %q, got:\n%s", want, got)
+	}
+}
+
+func TestGenerateOpenAPI(t *testing.T) {
+	c, err := readCatalog("testdata/catalog.yaml")
+	if err != nil {
+		t.Fatalf("readCatalog() error = %v", err)
+	}
+
+	got, err := generateOpenAPI(c)
+	if err != nil {
+		t.Fatalf("generateOpenAPI() error = %v", err)
+	}
+	if !strings.Contains(string(got), `"#/components/schemas/OrderLimitExceededProblem"`) {
+		t.Errorf("generateOpenAPI() should reference OrderLimitExceededProblem, got:\n%s", got)
+	}
+}
+
+func TestExportPackage(t *testing.T) {
+	got, err := exportPackage("github.com/tech4works/errors/cmd/errgen/testdata/defs", "typescript")
+	if err != nil {
+		t.Fatalf("exportPackage() error = %v", err)
+	}
+	want := `export type InvoiceOverdueProblem = Problem<"INVOICE_OVERDUE", { "number": unknown; "days": unknown }>;`
+	if !strings.Contains(string(got), want) {
+		t.Errorf("exportPackage() should contain %q, got:\n%s", want, got)
+	}
+
+	if _, err := exportPackage("github.com/tech4works/errors/cmd/errgen/testdata/defs", "go"); err == nil {
+		t.Error("exportPackage() should reject the go language")
+	}
+}
@@ -0,0 +1,112 @@
+// Command errgen generates Go error definitions from a YAML or JSON catalog. For every error of the
+// catalog it generates a sentinel Definition, a typed constructor and an Is helper, all built on top
+// of the github.com/tech4works/errors package.
+//
+// Usage:
+//
+//	//go:generate go run github.com/tech4works/errors/cmd/errgen -in errors.yaml -out errors_gen.go
+//
+// The same catalog can be exported for API clients and documentation with the -lang flag:
+//
+//	errgen -in errors.yaml -lang openapi -out errors.openapi.json
+//	errgen -in errors.yaml -lang typescript -out errors.ts
+//
+// Definitions declared in code with errors.Define are exported with the -from flag instead of -in. It
+// takes the import path of the package that declares them, which errgen imports in a temporary program
+// run from the current module to write errors.Catalog():
+//
+//	errgen -from example.com/app/apperrors -lang typescript -out errors.ts
+//
+// Programs can also export their definitions themselves, e.g. from a hidden command, with
+// errors.WriteOpenAPI(w, errors.Catalog()) and errors.WriteTypeScript(w, errors.Catalog()).
+//
+// The catalog has the following format:
+//
+//	package: apperrors
+//	errors:
+//	  - code: USER_NOT_FOUND
+//	    kind: NOT_FOUND
+//	    http_status: 404
+//	    message: "user {id} not found"
+//	    params:
+//	      - name: id
+//	        type: string
+//	    public_message: "The user could not be found."
+//	    docs_url: https://docs.example.com/errors/USER_NOT_FOUND
+//	    hints:
+//	      - "check that the user was not deleted"
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	in := flag.String("in", "errors.yaml", "path of the YAML or JSON error catalog")
+	out := flag.String("out", "errors_gen.go", "path of the generated Go file")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package of the generated file, overrides the catalog package")
+	lang := flag.String("lang", "go", "output language: go, openapi or typescript")
+	from := flag.String("from", "", "import path of a package whose errors.Define definitions are exported instead of -in")
+	flag.Usage = func() {
+		w := flag.CommandLine.Output()
+		fmt.Fprintln(w, "Usage: errgen [flags]")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Generates Go definitions from an error catalog, or exports it as OpenAPI or TypeScript.")
+		fmt.Fprintln(w, "Definitions declared in code with errors.Define are exported as OpenAPI or TypeScript")
+		fmt.Fprintln(w, "with -from, which imports their package from the current module.")
+		fmt.Fprintln(w)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var err error
+	if *from != "" {
+		err = runExport(*from, *out, *lang)
+	} else {
+		err = run(*in, *out, *pkg, *lang)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "errgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out, pkg, lang string) error {
+	c, err := readCatalog(in)
+	if err != nil {
+		return err
+	}
+
+	var bs []byte
+	switch lang {
+	case "go":
+		if pkg != "" {
+			c.Package = pkg
+		}
+		if c.Package == "" {
+			return fmt.Errorf("package is required, set it in the catalog or with -package")
+		}
+		bs, err = generate(c, filepath.Base(in))
+	case "openapi":
+		bs, err = generateOpenAPI(c)
+	case "typescript":
+		bs, err = generateTypeScript(c)
+	default:
+		return fmt.Errorf("unsupported language %q", lang)
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, bs, 0o644)
+}
+
+func runExport(from, out, lang string) error {
+	bs, err := exportPackage(from, lang)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, bs, 0o644)
+}
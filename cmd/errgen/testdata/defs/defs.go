@@ -0,0 +1,6 @@
+// Package defs declares definitions in code for the tests of errgen -from.
+package defs
+
+import "github.com/tech4works/errors"
+
+var ErrInvoiceOverdue = errors.Define("INVOICE_OVERDUE", errors.FailedPrecondition, "invoice {number} is overdue by {days} days")
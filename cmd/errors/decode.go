@@ -0,0 +1,151 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/tech4works/errors"
+)
+
+// decodeOptions configures how the decoded errors are printed.
+type decodeOptions struct {
+	// render are the options of the report printed for every error by errors.Render.
+	render []errors.RenderOption
+	only   bool
+	// graph is the format, "dot" or "mermaid", of the error trees printed instead of the errors.
+	graph string
+
+	// symbolizer resolves the PCStack of the errors, it is nil when no binary was given.
+	symbolizer *symbolizer
+}
+
+func runDecode(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("decode", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: errors decode [flags] [files...]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Pretty-prints the detailed errors found in the files, or in the standard input.")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+	parseOptions := decodeFlags(fs)
+	graph := fs.String("graph", "", "print the tree of every error, formed by the errors it wraps, as a dot or mermaid graph")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	opts, err := parseOptions()
+	if err != nil {
+		return err
+	}
+	switch *graph {
+	case "":
+	case "dot", "mermaid":
+		opts.graph = *graph
+		opts.only = true
+	default:
+		return fmt.Errorf("invalid -graph value %q", *graph)
+	}
+
+	return openInputs(fs.Args(), stdin, func(_ string, r io.Reader) error {
+		return decode(r, stdout, opts)
+	})
+}
+
+// decodeFlags defines the flags that configure the printing of the errors and returns the function
+// that builds the decodeOptions once the flags are parsed.
+func decodeFlags(fs *flag.FlagSet) func() (decodeOptions, error) {
+	color := fs.String("color", "auto", "colorize the output: auto, always or never")
+	showRuntime := fs.Bool("runtime", false, "show the runtime frames instead of collapsing them")
+	source := fs.Int("source", 2, "number of source lines shown around the application frames found on disk, negative to disable")
+	only := fs.Bool("only", false, "print only the decoded errors, dropping the other lines")
+
+	return func() (decodeOptions, error) {
+		opts := decodeOptions{only: *only}
+		switch *color {
+		case "auto":
+		case "always":
+			opts.render = append(opts.render, errors.RenderColors(true))
+		case "never":
+			opts.render = append(opts.render, errors.RenderColors(false), errors.RenderHyperlinks(""))
+		default:
+			return opts, fmt.Errorf("invalid -color value %q", *color)
+		}
+		opts.render = append(opts.render, errors.RenderRuntimeFrames(*showRuntime), errors.RenderSourceLines(*source))
+		return opts, nil
+	}
+}
+
+// decode copies r to w, replacing the lines that hold detailed errors with their report.
+func decode(r io.Reader, w io.Writer, opts decodeOptions) error {
+	return scan(r, func(line string, matches []match) error {
+		if len(matches) == 0 {
+			if !opts.only {
+				_, err := fmt.Fprintln(w, sanitize(line))
+				return err
+			}
+			return nil
+		}
+		for _, m := range matches {
+			var out string
+			switch opts.graph {
+			case "dot":
+				out = sanitize(errors.ToDOT(m.detail))
+			case "mermaid":
+				out = sanitize(errors.ToMermaid(m.detail))
+			default:
+				out = report(m, opts)
+			}
+			if _, err := io.WriteString(w, out); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// report returns the report of the decoded error, preceded by the text of its line that comes before
+// it. The frames of a PCStack are resolved by the symbolizer, when there is one.
+func report(m match, opts decodeOptions) string {
+	var prefix string
+	if m.prefix != "" {
+		prefix = sanitize(m.prefix) + "\n"
+	}
+
+	render := opts.render
+	if stack, ok := m.detail.PCStack(); ok {
+		if opts.symbolizer == nil {
+			render = append(slices.Clip(render), errors.RenderFrames(nil), errors.RenderWarning(fmt.Sprintf(
+				"%d program %s, resolve them with the symbolize command", len(stack.PCs), plural(len(stack.PCs), "counter"))))
+		} else {
+			frames, warning := opts.symbolizer.frames(stack)
+			render = append(slices.Clip(render), errors.RenderFrames(frames))
+			if warning != "" {
+				render = append(render, errors.RenderWarning(warning))
+			}
+		}
+	}
+	return prefix + errors.Render(m.detail, render...) + "\n"
+}
+
+// sanitize removes the C0 and C1 control characters of the decoded text, except for tabs and line
+// breaks, so a log line cannot move the cursor, change the colors or inject hyperlinks in the terminal.
+// The reports built by errors.Render are already sanitized.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r != '\t' && r != '\n' && (r < 0x20 || r >= 0x7f && r < 0xa0) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
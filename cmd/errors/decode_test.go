@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/tech4works/errors"
+)
+
+var errJobFailed = errors.Define("CMD_JOB_FAILED", errors.Internal, "job {job} failed after {attempts} attempts",
+	errors.Hint("check the worker logs"))
+
+func TestDecode(t *testing.T) {
+	err := errors.Wrap(errJobFailed.New("invoices", 3), "running schedule")
+	text := err.Error()
+	encoded, _ := json.Marshal(map[string]any{"level": "error", "error": err})
+	escaped := strings.NewReplacer("\n", `\n`, "\t", `\t`).Replace(text)
+	hostile, _ := json.Marshal(map[string]any{"error": errJobFailed.New("\x1b[2J\u009b", 1)})
+
+	tests := []struct {
+		name     string
+		input    string
+		opts     decodeOptions
+		colors   bool
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "Text with multi-line stack",
+			input:    "before\n2024/01/01 request failed: " + text + "\nafter\n",
+			contains: []string{"before\n", "2024/01/01 request failed:\nerror: running schedule: job invoices failed after 3 attempts\n", "errors/decode_test.go:", "TestDecode", "after\n"},
+			excludes: []string{"goroutine ", "[STACK]"},
+		},
+		{
+			name:     "Text with escaped stack",
+			input:    "msg=failed err=\"" + escaped + "\"\n",
+			contains: []string{"error: running schedule: job invoices failed after 3 attempts", "errors.TestDecode"},
+		},
+		{
+			name:     "JSON detail",
+			input:    string(encoded) + "\n",
+			contains: []string{"error: running schedule: job invoices failed after 3 attempts", "CMD_JOB_FAILED (INTERNAL)", "attempts=3 job=invoices", "check the worker logs", "    └─ job invoices failed after 3 attempts  "},
+		},
+		{
+			name:     "Only errors",
+			input:    "before\n" + string(encoded) + "\nafter\n",
+			opts:     decodeOptions{only: true},
+			contains: []string{"error: running schedule"},
+			excludes: []string{"before\n", "after\n"},
+		},
+		{
+			name:     "Colors",
+			input:    string(encoded) + "\n",
+			colors:   true,
+			contains: []string{"\x1b[31m", "\x1b[0m"},
+		},
+		{
+			name:     "Control characters",
+			input:    "\x1b]8;;https://evil.example\x07plain\n\x1b[31mrequest failed: " + text + "\n" + string(hostile) + "\n",
+			contains: []string{"]8;;https://evil.exampleplain\n", "[31mrequest failed:\n", "job=[2J\n"},
+			excludes: []string{"\x1b", "\x07", "\u009b"},
+		},
+		{
+			name:     "Not detailed",
+			input:    "[CAUSE]: incomplete\n",
+			contains: []string{"[CAUSE]: incomplete\n"},
+			excludes: []string{"error:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			tt.opts.render = append(tt.opts.render, errors.RenderColors(tt.colors), errors.RenderHyperlinks(""))
+			if err := decode(strings.NewReader(tt.input), &out, tt.opts); err != nil {
+				t.Fatalf("decode() error = %v", err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(out.String(), s) {
+					t.Errorf("decode() output does not contain %q:\n%s", s, out.String())
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(out.String(), s) {
+					t.Errorf("decode() output contains %q:\n%s", s, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"decode", "-color", "never"}, strings.NewReader("plain\n"), &stdout, &stderr); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if stdout.String() != "plain\n" {
+		t.Errorf("run() output = %q, want %q", stdout.String(), "plain\n")
+	}
+	if err := run([]string{"unknown"}, nil, &stdout, &stderr); err == nil {
+		t.Error("run() should fail for an unknown command")
+	}
+}
+
+func TestRunDecodeGraph(t *testing.T) {
+	encoded, _ := json.Marshal(errors.Wrap(errJobFailed.New("invoices", 3), "running schedule"))
+	input := "before\n" + string(encoded) + "\n"
+
+	tests := []struct {
+		format   string
+		contains []string
+	}{
+		{"dot", []string{"digraph errors {\n", `n0 -> n1 [label="wraps"];`, `job invoices failed after 3 attempts`}},
+		{"mermaid", []string{"flowchart TD\n", "n0 -->|wraps| n1\n", "job invoices failed after 3 attempts"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if err := runDecode([]string{"-graph", tt.format}, strings.NewReader(input), &stdout, &stderr); err != nil {
+				t.Fatalf("runDecode() error = %v", err)
+			}
+			if strings.Contains(stdout.String(), "before") {
+				t.Errorf("runDecode() output contains the log lines:\n%s", stdout.String())
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(stdout.String(), s) {
+					t.Errorf("runDecode() output does not contain %q:\n%s", s, stdout.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Command errors inspects the detailed errors of the github.com/tech4works/errors package found in logs.
+//
+// Usage:
+//
+//	errors <command> [flags] [files...]
+//
+// The commands are:
+//
+//	decode    pretty-print the detailed errors found in the input
+//	stats     group the errors found in the input and count them
+//	symbolize pretty-print the errors resolving their program counters
+//
+// Every command reads the given files, or the standard input when no file is given, and recognizes both
+// the "[CAUSE]: ... [STACK]: ..." strings returned by Detail.Error and the JSON encoded errors written by
+// Detail.MarshalJSON, even when they are embedded in structured log lines.
+//
+// Example:
+//
+//	kubectl logs deploy/api | errors decode -only
+//	errors stats -by code -top 5 /var/lib/app/errors*.jsonl
+//	errors symbolize -bin ./bin/api errors.jsonl
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// command is a subcommand of the tool.
+type command struct {
+	name  string
+	short string
+	run   func(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+var commands = []command{
+	{"decode", "pretty-print the detailed errors found in the input", runDecode},
+	{"stats", "group the errors found in the input and count them", runStats},
+	{"symbolize", "pretty-print the errors resolving their program counters", runSymbolize},
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "errors:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		usage(stderr)
+		return nil
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:], stdin, stdout, stderr)
+		}
+	}
+	usage(stderr)
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: errors <command> [flags] [files...]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.short)
+	}
+}
+
+// openInputs calls fn for the standard input, when no file is given, or for every given file in order.
+// Files with the ".gz" extension, such as the compressed segments of a journal, are decompressed.
+func openInputs(files []string, stdin io.Reader, fn func(name string, r io.Reader) error) error {
+	if len(files) == 0 {
+		return fn("<stdin>", stdin)
+	}
+	for _, name := range files {
+		if err := openInput(name, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func openInput(name string, fn func(name string, r io.Reader) error) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(name, ".gz") {
+		zr, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+	return fn(name, r)
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tech4works/errors"
+)
+
+// causeMarker is the prefix of the string returned by Detail.Error.
+const causeMarker = "[CAUSE]: "
+
+// match is a detailed error found in the input.
+type match struct {
+	// prefix is the text of the log line before the error, such as a timestamp or a log message.
+	prefix string
+	// detail is the decoded error.
+	detail *errors.Detail
+	// time is the time of the log line or journal record, zero when it is unknown.
+	time time.Time
+	// fingerprint is the fingerprint recorded along with the error, such as the one of a journal record.
+	fingerprint string
+}
+
+// scan reads r line by line and calls fn for every line, with the detailed errors found in it. The lines
+// of a debug stack printed after a "[CAUSE]: ... [STACK]: ..." string are consumed as part of the error,
+// so they are joined to the line that holds the error.
+func scan(r io.Reader, fn func(line string, matches []match) error) error {
+	lr := lineReader{s: bufio.NewScanner(r)}
+	lr.s.Buffer(make([]byte, 0, 64*1024), 16<<20)
+
+	for {
+		line, ok := lr.next()
+		if !ok {
+			return lr.s.Err()
+		}
+
+		var matches []match
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "{") && json.Valid([]byte(trimmed)) {
+			var v any
+			_ = json.Unmarshal([]byte(trimmed), &v)
+			matches = findJSON(v, nil)
+			if object, ok := v.(map[string]any); ok {
+				for i := range matches {
+					matches[i].time = jsonTime(object)
+				}
+			}
+		} else if idx := strings.Index(line, causeMarker); idx >= 0 {
+			for lr.continuesStack() {
+				next, _ := lr.next()
+				line += "\n" + next
+			}
+			if detail := parseText(line[idx:]); detail != nil {
+				prefix := strings.TrimSpace(line[:idx])
+				matches = append(matches, match{prefix: prefix, detail: detail, time: prefixTime(prefix)})
+			}
+		}
+
+		if err := fn(line, matches); err != nil {
+			return err
+		}
+	}
+}
+
+// lineReader reads lines, allowing to look ahead the next two lines.
+type lineReader struct {
+	s       *bufio.Scanner
+	pending []string
+}
+
+func (lr *lineReader) peek(n int) (string, bool) {
+	for len(lr.pending) <= n {
+		if !lr.s.Scan() {
+			return "", false
+		}
+		lr.pending = append(lr.pending, lr.s.Text())
+	}
+	return lr.pending[n], true
+}
+
+func (lr *lineReader) next() (string, bool) {
+	line, ok := lr.peek(0)
+	if ok {
+		lr.pending = lr.pending[1:]
+	}
+	return line, ok
+}
+
+// continuesStack reports whether the next line continues a debug stack, which alternates function
+// lines with tab indented location lines.
+func (lr *lineReader) continuesStack() bool {
+	line, ok := lr.peek(0)
+	if !ok {
+		return false
+	}
+	if strings.HasPrefix(line, "\t") {
+		return true
+	}
+	following, ok := lr.peek(1)
+	return line != "" && ok && strings.HasPrefix(following, "\t")
+}
+
+// findJSON collects the detailed errors of a decoded JSON value: objects with the shape written by
+// Detail.MarshalJSON and strings holding a "[CAUSE]: ... [STACK]: ..." error.
+func findJSON(v any, matches []match) []match {
+	switch x := v.(type) {
+	case string:
+		if idx := strings.Index(x, causeMarker); idx >= 0 {
+			if detail := parseText(x[idx:]); detail != nil {
+				matches = append(matches, match{prefix: strings.TrimSpace(x[:idx]), detail: detail})
+			}
+		}
+	case []any:
+		for _, item := range x {
+			matches = findJSON(item, matches)
+		}
+	case map[string]any:
+		if isDetailJSON(x) {
+			bs, _ := json.Marshal(x)
+			var detail errors.Detail
+			if err := json.Unmarshal(bs, &detail); err == nil {
+				return append(matches, match{detail: &detail})
+			}
+		}
+		n := len(matches)
+		for _, key := range sortedKeys(x) {
+			matches = findJSON(x[key], matches)
+		}
+		if fingerprint, ok := x["fingerprint"].(string); ok {
+			for i := n; i < len(matches); i++ {
+				if matches[i].fingerprint == "" {
+					matches[i].fingerprint = fingerprint
+				}
+			}
+		}
+	}
+	return matches
+}
+
+func isDetailJSON(v map[string]any) bool {
+	_, hasMessage := v["message"].(string)
+	_, hasFile := v["file"].(string)
+	_, hasStack := v["stack"].(string)
+	return hasMessage && (hasFile || hasStack)
+}
+
+// parseText decodes a "[CAUSE]: ... [STACK]: ..." string, unescaping the line breaks and tabs of the
+// stack when the log writer escaped them. It returns nil when s is not a detailed error.
+func parseText(s string) *errors.Detail {
+	if !strings.Contains(s, "\n") && strings.Contains(s, `\n`) {
+		s = strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\"`, `"`, `\\`, `\`).Replace(s)
+	}
+	err := stderrors.New(s)
+	if !errors.IsDetailed(err) {
+		return nil
+	}
+	return errors.Details(err)
+}
+
+// jsonTime returns the time of a structured log line or journal record, read from the usual time keys
+// as an RFC 3339 string or as Unix seconds.
+func jsonTime(object map[string]any) time.Time {
+	for _, key := range []string{"time", "ts", "timestamp", "@timestamp"} {
+		switch v := object[key].(type) {
+		case string:
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t
+			}
+		case float64:
+			sec, frac := math.Modf(v)
+			return time.Unix(int64(sec), int64(frac*1e9)).UTC()
+		}
+	}
+	return time.Time{}
+}
+
+// prefixLayouts are the layouts of the timestamps recognized at the start of a text log line.
+var prefixLayouts = []string{
+	time.RFC3339Nano,
+	"2006/01/02 15:04:05.000000",
+	"2006/01/02 15:04:05",
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05",
+}
+
+// prefixTime returns the timestamp at the start of a text log line, such as the one written by the log
+// package, or the zero time when there is none.
+func prefixTime(prefix string) time.Time {
+	prefix = strings.TrimPrefix(prefix, "time=")
+	for _, layout := range prefixLayouts {
+		n := len(layout)
+		if layout == time.RFC3339Nano {
+			n = strings.IndexByte(prefix+" ", ' ')
+		}
+		if n > len(prefix) {
+			continue
+		}
+		if t, err := time.ParseInLocation(layout, prefix[:n], time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tech4works/errors"
+)
+
+// statsOptions configures how the errors are grouped and printed.
+type statsOptions struct {
+	by   string
+	top  int
+	json bool
+}
+
+// group is a set of errors with the same key.
+type group struct {
+	Key       string     `json:"key"`
+	Count     int        `json:"count"`
+	FirstSeen *time.Time `json:"first_seen,omitempty"`
+	LastSeen  *time.Time `json:"last_seen,omitempty"`
+	Codes     []string   `json:"codes,omitempty"`
+	CallSites []callSite `json:"call_sites"`
+	Examples  []string   `json:"examples"`
+	sites     map[string]*callSite
+}
+
+// callSite is a location where the errors of a group were created.
+type callSite struct {
+	Site  string `json:"site"`
+	Count int    `json:"count"`
+}
+
+// maxExamples is the number of distinct example messages kept by group.
+const maxExamples = 3
+
+func runStats(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: errors stats [flags] [files...]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Groups the detailed errors found in the log files or JSONL journals, or in the standard")
+		fmt.Fprintln(stderr, "input, and prints the groups with the most errors first.")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+	by := fs.String("by", "fingerprint", "group the errors by fingerprint, code or func")
+	top := fs.Int("top", 10, "number of groups and call sites to print, 0 prints all of them")
+	asJSON := fs.Bool("json", false, "print the groups as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	opts := statsOptions{by: *by, top: *top, json: *asJSON}
+	if opts.by != "fingerprint" && opts.by != "code" && opts.by != "func" {
+		return fmt.Errorf("invalid -by value %q", opts.by)
+	}
+
+	s := newStats(opts)
+	err := openInputs(fs.Args(), stdin, func(_ string, r io.Reader) error {
+		return scan(r, func(_ string, matches []match) error {
+			for _, m := range matches {
+				s.add(m)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return s.write(stdout)
+}
+
+// stats aggregates the errors found in the input by the key selected with the by option.
+type stats struct {
+	opts   statsOptions
+	total  int
+	groups map[string]*group
+}
+
+func newStats(opts statsOptions) *stats {
+	return &stats{opts: opts, groups: map[string]*group{}}
+}
+
+func (s *stats) add(m match) {
+	key := s.key(m)
+	g, ok := s.groups[key]
+	if !ok {
+		g = &group{Key: key, sites: map[string]*callSite{}}
+		s.groups[key] = g
+	}
+	s.total++
+	g.Count++
+
+	if !m.time.IsZero() {
+		t := m.time
+		if g.FirstSeen == nil || t.Before(*g.FirstSeen) {
+			g.FirstSeen = &t
+		}
+		if g.LastSeen == nil || t.After(*g.LastSeen) {
+			g.LastSeen = &t
+		}
+	}
+	if code := m.detail.Code(); code != "" && !slices.Contains(g.Codes, code) {
+		g.Codes = append(g.Codes, code)
+	}
+
+	site := origin(m.detail)
+	if g.sites[site] == nil {
+		g.sites[site] = &callSite{Site: site}
+	}
+	g.sites[site].Count++
+
+	if msg := m.detail.Message(); len(g.Examples) < maxExamples && !slices.Contains(g.Examples, msg) {
+		g.Examples = append(g.Examples, msg)
+	}
+}
+
+func (s *stats) key(m match) string {
+	switch s.opts.by {
+	case "code":
+		if code := m.detail.Code(); code != "" {
+			return code
+		}
+		return "(no code)"
+	case "func":
+		return funcKey(m.detail)
+	default:
+		if m.fingerprint != "" {
+			return m.fingerprint
+		}
+		return errors.Fingerprint(m.detail)
+	}
+}
+
+// sorted returns the groups with the most errors first, limited by the top option, with their call
+// sites sorted the same way.
+func (s *stats) sorted() []*group {
+	groups := make([]*group, 0, len(s.groups))
+	for _, g := range s.groups {
+		g.CallSites = g.CallSites[:0]
+		for _, site := range g.sites {
+			g.CallSites = append(g.CallSites, *site)
+		}
+		sort.Slice(g.CallSites, func(i, k int) bool {
+			if g.CallSites[i].Count != g.CallSites[k].Count {
+				return g.CallSites[i].Count > g.CallSites[k].Count
+			}
+			return g.CallSites[i].Site < g.CallSites[k].Site
+		})
+		g.CallSites = limit(g.CallSites, s.opts.top)
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, k int) bool {
+		if groups[i].Count != groups[k].Count {
+			return groups[i].Count > groups[k].Count
+		}
+		return groups[i].Key < groups[k].Key
+	})
+	return limit(groups, s.opts.top)
+}
+
+func (s *stats) write(w io.Writer) error {
+	groups := s.sorted()
+	if s.opts.json {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{
+			"total":  s.total,
+			"by":     s.opts.by,
+			"groups": groups,
+		})
+	}
+
+	p := printer{w: w}
+	p.printf("%d %s in %d %s\n", s.total, plural(s.total, "error"), len(s.groups), plural(len(s.groups), "group"))
+	for _, g := range groups {
+		p.printf("\n%d × %s\n", g.Count, g.Key)
+		if len(g.Codes) > 0 && s.opts.by != "code" {
+			p.list("code", g.Codes)
+		}
+		if g.FirstSeen != nil {
+			p.field("first seen", g.FirstSeen.Format(time.RFC3339))
+			p.field("last seen", g.LastSeen.Format(time.RFC3339))
+		}
+		var sites []string
+		for _, site := range g.CallSites {
+			sites = append(sites, fmt.Sprintf("%s (%d)", site.Site, site.Count))
+		}
+		p.list("call site", sites)
+		p.list("example", g.Examples)
+	}
+	return p.err
+}
+
+// printer writes the summary of the stats, keeping the first write error.
+type printer struct {
+	w   io.Writer
+	err error
+}
+
+func (p *printer) printf(format string, args ...any) {
+	if p.err == nil {
+		_, p.err = io.WriteString(p.w, sanitize(fmt.Sprintf(format, args...)))
+	}
+}
+
+// field prints an attribute of a group with its label aligned to the others.
+func (p *printer) field(label, value string) {
+	p.printf("  %-10s %s\n", label, value)
+}
+
+// list prints the values of a field, one per line, aligned under the first one.
+func (p *printer) list(label string, values []string) {
+	for i, value := range values {
+		if i > 0 {
+			label = ""
+		}
+		p.field(label, value)
+	}
+}
+
+// funcKey returns the function where the detailed error was created in the qualified form, e.g.
+// "service.(*Users).Get", whatever the function names configured in the program that logged it. The name
+// comes from the stack when there is one, otherwise from the recorded function, which is qualified by the
+// directory of its file when it was recorded without its package.
+func funcKey(detail *errors.Detail) string {
+	if frames := detail.Frames(); len(frames) > 0 {
+		return path.Base(frames[0].Function)
+	}
+	name := path.Base(detail.Func())
+	if !strings.Contains(name, ".") {
+		name = path.Base(path.Dir(detail.File())) + "." + name
+	}
+	return name
+}
+
+// origin returns the call site where the detailed error was created.
+func origin(detail *errors.Detail) string {
+	return fmt.Sprintf("%s:%d %s", detail.File(), detail.Line(), detail.Func())
+}
+
+func limit[T any](s []T, n int) []T {
+	if n > 0 && len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tech4works/errors"
+	"github.com/tech4works/errors/journal"
+)
+
+func TestStats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.jsonl")
+	j, err := journal.Open(path, journal.Options{})
+	if err != nil {
+		t.Fatalf("journal.Open() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		j.Report(errJobFailed.New("invoices", i))
+	}
+	j.Report(errors.New("disk full"))
+	_ = j.Close()
+
+	log := "2024/01/02 10:00:00 failed: " + errors.New("timeout").Error() + "\n" +
+		"2024/01/02 09:00:00 failed: " + errors.New("timeout").Error() + "\n"
+
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		contains []string
+	}{
+		{
+			name:     "Journal by fingerprint",
+			args:     []string{path},
+			contains: []string{"4 errors in 2 groups\n", "\n3 × ", "CMD_JOB_FAILED", "job invoices failed after 0 attempts", "job invoices failed after 2 attempts", "stats_test.go:"},
+		},
+		{
+			name:     "Journal by code",
+			args:     []string{"-by", "code", path},
+			contains: []string{"3 × CMD_JOB_FAILED\n", "1 × (no code)\n"},
+		},
+		{
+			name:     "Log by func",
+			args:     []string{"-by", "func"},
+			stdin:    log,
+			contains: []string{"2 errors in 1 group\n", "2 × errors.TestStats\n", "2024-01-02T09:00:00", "2024-01-02T10:00:00", "TestStats (1)"},
+		},
+		{
+			name:     "Log by func without stack",
+			args:     []string{"-by", "func"},
+			stdin:    log + `{"error":{"message":"timeout","file":"errors/stats_test.go","line":26,"func":"TestStats"}}` + "\n",
+			contains: []string{"3 errors in 1 group\n", "3 × errors.TestStats\n"},
+		},
+		{
+			name:     "Top groups",
+			args:     []string{"-top", "1", path},
+			contains: []string{"4 errors in 2 groups\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if err := runStats(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr); err != nil {
+				t.Fatalf("runStats() error = %v", err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(stdout.String(), s) {
+					t.Errorf("runStats() output does not contain %q:\n%s", s, stdout.String())
+				}
+			}
+			if tt.name == "Top groups" && strings.Count(stdout.String(), " × ") != 1 {
+				t.Errorf("runStats() output has more than one group:\n%s", stdout.String())
+			}
+		})
+	}
+}
+
+func TestStatsJSON(t *testing.T) {
+	input := "2024-01-02T10:00:00Z " + errors.New("timeout").Error() + "\n"
+
+	var stdout, stderr bytes.Buffer
+	if err := runStats([]string{"-json", "-by", "code"}, strings.NewReader(input), &stdout, &stderr); err != nil {
+		t.Fatalf("runStats() error = %v", err)
+	}
+
+	var got struct {
+		Total  int `json:"total"`
+		Groups []struct {
+			Key       string     `json:"key"`
+			Count     int        `json:"count"`
+			FirstSeen string     `json:"first_seen"`
+			CallSites []callSite `json:"call_sites"`
+			Examples  []string   `json:"examples"`
+		} `json:"groups"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("runStats() output is not JSON: %v\n%s", err, stdout.String())
+	}
+	if got.Total != 1 || len(got.Groups) != 1 || got.Groups[0].Count != 1 || got.Groups[0].FirstSeen != "2024-01-02T10:00:00Z" ||
+		got.Groups[0].Examples[0] != "timeout" || len(got.Groups[0].CallSites) != 1 {
+		t.Errorf("runStats() = %+v", got)
+	}
+}
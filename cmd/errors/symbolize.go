@@ -0,0 +1,198 @@
+package main
+
+import (
+	"debug/dwarf"
+	"debug/elf"
+	"debug/gosym"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/tech4works/errors"
+)
+
+func runSymbolize(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("symbolize", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: errors symbolize -bin <binary> [flags] [files...]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Pretty-prints the detailed errors found in the files, or in the standard input, resolving")
+		fmt.Fprintln(stderr, "the program counters captured with the StackPCs mode against the ELF binary that produced them.")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+	bin := fs.String("bin", "", "path of the binary that produced the errors")
+	parseOptions := decodeFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *bin == "" {
+		fs.Usage()
+		return fmt.Errorf("the -bin flag is required")
+	}
+	opts, err := parseOptions()
+	if err != nil {
+		return err
+	}
+	if opts.symbolizer, err = openSymbolizer(*bin); err != nil {
+		return err
+	}
+
+	return openInputs(fs.Args(), stdin, func(_ string, r io.Reader) error {
+		return decode(r, stdout, opts)
+	})
+}
+
+// symbolizer resolves program counters against the symbol table of a Go ELF binary.
+type symbolizer struct {
+	path    string
+	buildID string
+	table   *gosym.Table
+	// anchor is the address of the errors.PCAnchorSymbol function in the binary.
+	anchor uint64
+	// inlined are the address ranges of the inlined calls, read from the DWARF data of the binary, which
+	// name the functions that the Go symbol table attributes to their callers.
+	inlined []inlineRange
+}
+
+// inlineRange is an address range of the code of a function inlined into another.
+type inlineRange struct {
+	low, high uint64
+	depth     int
+	function  string
+}
+
+func openSymbolizer(path string) (*symbolizer, error) {
+	file, err := elf.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	text := file.Section(".text")
+	pclntab := file.Section(".gopclntab")
+	if text == nil || pclntab == nil {
+		return nil, fmt.Errorf("%s has no Go symbol table", path)
+	}
+	pclnData, err := pclntab.Data()
+	if err != nil {
+		return nil, err
+	}
+	var symData []byte
+	if symtab := file.Section(".gosymtab"); symtab != nil {
+		if symData, err = symtab.Data(); err != nil {
+			return nil, err
+		}
+	}
+	table, err := gosym.NewTable(symData, gosym.NewLineTable(pclnData, text.Addr))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	s := &symbolizer{path: path, table: table}
+	s.buildID, _ = errors.ReadBuildID(path)
+	if fn := table.LookupFunc(errors.PCAnchorSymbol); fn != nil {
+		s.anchor = fn.Entry
+	}
+	if data, dwarfErr := file.DWARF(); dwarfErr == nil {
+		s.inlined = inlineRanges(data)
+	}
+	return s, nil
+}
+
+// inlineRanges reads the address ranges of the inlined calls of the DWARF data, ignoring the entries
+// that cannot be read, since they only refine the function names.
+func inlineRanges(data *dwarf.Data) []inlineRange {
+	var ranges []inlineRange
+	names := map[dwarf.Offset]string{}
+	name := func(offset dwarf.Offset) string {
+		if n, ok := names[offset]; ok {
+			return n
+		}
+		r := data.Reader()
+		r.Seek(offset)
+		entry, err := r.Next()
+		if err != nil || entry == nil {
+			return ""
+		}
+		n, _ := entry.Val(dwarf.AttrName).(string)
+		names[offset] = n
+		return n
+	}
+
+	depth := 0
+	r := data.Reader()
+	for {
+		entry, err := r.Next()
+		if err != nil || entry == nil {
+			return ranges
+		}
+		if entry.Tag == 0 {
+			depth--
+			continue
+		}
+		if entry.Tag == dwarf.TagInlinedSubroutine {
+			origin, _ := entry.Val(dwarf.AttrAbstractOrigin).(dwarf.Offset)
+			if function := name(origin); function != "" {
+				pcs, _ := data.Ranges(entry)
+				for _, pc := range pcs {
+					ranges = append(ranges, inlineRange{low: pc[0], high: pc[1], depth: depth, function: function})
+				}
+			}
+		}
+		if entry.Children {
+			depth++
+		}
+	}
+}
+
+// inlinedFunction returns the innermost function inlined at pc, or an empty string when pc is not in
+// the code of an inlined call.
+func (s *symbolizer) inlinedFunction(pc uint64) string {
+	function, depth := "", -1
+	for _, r := range s.inlined {
+		if r.low <= pc && pc < r.high && r.depth > depth {
+			function, depth = r.function, r.depth
+		}
+	}
+	return function
+}
+
+// frames resolves the program counters of the stack, relocating them by the difference between the
+// runtime and the binary addresses of the anchor, and returns a warning when the stack does not seem to
+// come from the binary.
+func (s *symbolizer) frames(stack errors.PCStack) ([]errors.Frame, string) {
+	var warning string
+	switch {
+	case stack.BuildID != "" && s.buildID != "" && stack.BuildID != s.buildID:
+		warning = fmt.Sprintf("build ID %s differs from %s of %s", stack.BuildID, s.buildID, s.path)
+	case s.anchor == 0:
+		warning = fmt.Sprintf("%s does not link %s, the frames may be wrong", s.path, errors.PCAnchorSymbol)
+	}
+
+	var slide uint64
+	if s.anchor != 0 {
+		slide = uint64(stack.Anchor) - s.anchor
+	}
+
+	frames := make([]errors.Frame, 0, len(stack.PCs))
+	for _, pc := range stack.PCs {
+		// The captured program counters are return addresses, so the call instruction is the one before.
+		addr := uint64(pc) - slide - 1
+		file, line, fn := s.table.PCToLine(addr)
+		if fn == nil {
+			frames = append(frames, errors.Frame{Function: fmt.Sprintf("0x%x", uint64(pc)), File: "?"})
+			continue
+		}
+		// runtime.Callers records a program counter for every inlined call, and one pointing at its call
+		// site for each function it was inlined into, so every program counter resolves to the innermost
+		// function only, as runtime.CallersFrames does; the outer frames come from their own counters.
+		function := fn.Name
+		if inlined := s.inlinedFunction(addr); inlined != "" {
+			function = inlined
+		}
+		frames = append(frames, errors.Frame{Function: function, File: file, Line: line})
+	}
+	return frames, warning
+}
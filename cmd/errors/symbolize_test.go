@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/tech4works/errors"
+)
+
+func TestSymbolize(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("symbolization requires an ELF binary")
+	}
+	bin, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errors.SetStackCapture(errors.StackPCs)
+	detail := errors.Details(errors.New("timeout"))
+	errors.SetStackCapture(errors.StackFull)
+
+	encoded, _ := json.Marshal(detail)
+	mismatch := strings.Replace(detail.Error(), "build="+errors.BuildID(), "build=other", 1)
+
+	tests := []struct {
+		name     string
+		input    string
+		contains []string
+	}{
+		{
+			name:     "JSON detail",
+			input:    string(encoded) + "\n",
+			contains: []string{"error: timeout\n", "  at github.com/tech4works/errors/cmd/errors.TestSymbolize ", fmt.Sprintf("symbolize_test.go:%d", detail.Line())},
+		},
+		{
+			name:     "Text detail",
+			input:    "failed: " + detail.Error() + "\n",
+			contains: []string{"errors.TestSymbolize", fmt.Sprintf("symbolize_test.go:%d", detail.Line())},
+		},
+		{
+			name:     "Build ID mismatch",
+			input:    mismatch + "\n",
+			contains: []string{"warning: build ID other differs", "errors.TestSymbolize"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := runSymbolize([]string{"-bin", bin, "-color", "never"}, strings.NewReader(tt.input), &stdout, &stderr)
+			if err != nil {
+				t.Fatalf("runSymbolize() error = %v", err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(stdout.String(), s) {
+					t.Errorf("runSymbolize() output does not contain %q:\n%s", s, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestDecodePCStack(t *testing.T) {
+	errors.SetStackCapture(errors.StackPCs)
+	detail := errors.Details(errors.New("timeout"))
+	errors.SetStackCapture(errors.StackFull)
+
+	var out bytes.Buffer
+	if err := decode(strings.NewReader(detail.Error()+"\n"), &out, decodeOptions{}); err != nil {
+		t.Fatalf("decode() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "program counters, resolve them with the symbolize command") {
+		t.Errorf("decode() output = %s", out.String())
+	}
+}
+
+func TestRunSymbolizeWithoutBinary(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if err := runSymbolize(nil, strings.NewReader(""), &stdout, &stderr); err == nil {
+		t.Error("runSymbolize() should fail without -bin")
+	}
+}
+
+func TestSymbolizeInlined(t *testing.T) {
+	if testing.Short() || runtime.GOOS != "linux" {
+		t.Skip("building the inlined program requires the go command and an ELF binary")
+	}
+	goCmd, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go command is not available")
+	}
+
+	// Test binaries have no DWARF data, which names the inlined calls, so the errors come from a program
+	// built for the test, which also tells the frames it resolves with runtime.CallersFrames.
+	bin := filepath.Join(t.TempDir(), "inlined")
+	if out, err := exec.Command(goCmd, "build", "-o", bin, "./testdata/inlined").CombinedOutput(); err != nil {
+		t.Fatalf("go build error = %v\n%s", err, out)
+	}
+	out, err := exec.Command(bin).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var samples []struct {
+		Stack  string         `json:"stack"`
+		Frames []errors.Frame `json:"frames"`
+	}
+	if err = json.Unmarshal(out, &samples); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := openSymbolizer(bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sample := range samples {
+		stack, ok := errors.ParsePCStack(sample.Stack)
+		if !ok {
+			t.Fatalf("ParsePCStack(%q) failed", sample.Stack)
+		}
+		frames, warning := s.frames(stack)
+		if warning != "" {
+			t.Errorf("symbolizer.frames() warning = %v", warning)
+		}
+		if !strings.HasSuffix(sample.Frames[0].Function, ".newTimeout") {
+			t.Errorf("the first frame is %v, want the inlined newTimeout", sample.Frames[0])
+		}
+		if fmt.Sprint(frames) != fmt.Sprint(sample.Frames) {
+			t.Errorf("symbolizer.frames() =\n%v\nwant\n%v", frames, sample.Frames)
+		}
+	}
+}
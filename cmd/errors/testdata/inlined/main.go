@@ -0,0 +1,30 @@
+// Command inlined prints, as JSON, errors whose stack was captured as program counters from an inlined
+// call, along with the frames the program resolves for them, so the symbolize tests can compare them.
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/tech4works/errors"
+)
+
+type sample struct {
+	Stack  string         `json:"stack"`
+	Frames []errors.Frame `json:"frames"`
+}
+
+// newTimeout is small enough to be inlined into its callers.
+func newTimeout() error {
+	return errors.New("timeout")
+}
+
+func main() {
+	var samples []sample
+	for _, depth := range []int{0, 1} {
+		errors.Configure(errors.ConfigStack(errors.StackPCs), errors.ConfigStackDepth(depth))
+		detail := errors.Details(newTimeout())
+		samples = append(samples, sample{Stack: detail.Stack(), Frames: detail.Frames()})
+	}
+	_ = json.NewEncoder(os.Stdout).Encode(samples)
+}
@@ -0,0 +1,339 @@
+package errors
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// PathMode defines how the file of the origin of an error is recorded.
+type PathMode int
+
+const (
+	// PathShort records the file with its directory, e.g. "service/user.go". It is the default mode.
+	PathShort PathMode = iota
+	// PathBase records only the name of the file, e.g. "user.go".
+	PathBase
+	// PathFull records the absolute path of the file, e.g. "/src/app/service/user.go".
+	PathFull
+)
+
+// FuncMode defines how the function of the origin of an error is recorded.
+type FuncMode int
+
+const (
+	// FuncShort records the last element of the function name, e.g. "Find", or "func1" for a closure.
+	// It is the default mode.
+	FuncShort FuncMode = iota
+	// FuncQualified records the function name qualified by its package name, e.g. "service.(*Users).Find".
+	FuncQualified
+	// FuncFull records the fully qualified function name, e.g. "github.com/org/app/service.(*Users).Find".
+	FuncFull
+)
+
+// ErrorFormat defines the string returned by Detail.Error.
+type ErrorFormat int
+
+const (
+	// ErrorFormatFull returns the cause and the stack of the error, as in
+	// "[CAUSE]: (file.go:10) Func: message [STACK]: stack", which can be parsed back by Details. It is the
+	// default format.
+	ErrorFormatFull ErrorFormat = iota
+	// ErrorFormatCause returns the cause of the error, as in "(file.go:10) Func: message".
+	ErrorFormatCause
+	// ErrorFormatMessage returns only the message of the error.
+	ErrorFormatMessage
+)
+
+// Redacted is the value that replaces the values of the fields selected by the redaction policy.
+const Redacted = "[REDACTED]"
+
+// Config holds the global options of the package, which are set by Configure.
+type Config struct {
+	// Stack is how the errors capture the stack of the goroutine that creates them.
+	Stack StackCapture
+	// StackDepth is the maximum number of frames captured from the origin of the error, where 0 means
+	// every frame, or 64 frames with the StackPCs mode.
+	StackDepth int
+	// Paths is how the file of the origin of the errors is recorded.
+	Paths PathMode
+	// FuncNames is how the function of the origin of the errors is recorded.
+	FuncNames FuncMode
+	// Format is the string returned by Detail.Error.
+	Format ErrorFormat
+	// Metadata is the combination of the metadata fields stamped onto the errors.
+	Metadata MetadataField
+	// RedactKeys are the keys, compared case-insensitively, of the fields whose values are replaced by
+	// Redacted.
+	RedactKeys []string
+	// Redactor, when set, is called with every field that is not selected by RedactKeys and returns the
+	// value to be exposed in its place.
+	Redactor func(key string, value any) any
+}
+
+// ConfigOption configures the global options of the package.
+type ConfigOption func(c *Config)
+
+// ConfigStack sets how the errors capture their stack. The default is StackFull.
+func ConfigStack(mode StackCapture) ConfigOption {
+	return func(c *Config) {
+		c.Stack = mode
+	}
+}
+
+// ConfigStackDepth sets the maximum number of frames captured from the origin of the errors. A depth of 0,
+// the default, captures every frame.
+func ConfigStackDepth(depth int) ConfigOption {
+	return func(c *Config) {
+		c.StackDepth = max(depth, 0)
+	}
+}
+
+// ConfigPaths sets how the file of the origin of the errors is recorded. The default is PathShort.
+func ConfigPaths(mode PathMode) ConfigOption {
+	return func(c *Config) {
+		c.Paths = mode
+	}
+}
+
+// ConfigFuncNames sets how the function of the origin of the errors is recorded. The default is FuncShort.
+func ConfigFuncNames(mode FuncMode) ConfigOption {
+	return func(c *Config) {
+		c.FuncNames = mode
+	}
+}
+
+// ConfigFormat sets the string returned by Detail.Error. The default is ErrorFormatFull, the only format
+// that Details and IsDetailed can parse back from text, such as a log line. Errors that are, or wrap, a
+// *Detail are recognized by Details and IsDetailed under every format.
+func ConfigFormat(format ErrorFormat) ConfigOption {
+	return func(c *Config) {
+		c.Format = format
+	}
+}
+
+// ConfigMetadata sets the metadata fields stamped onto the errors. The default is MetadataNone.
+func ConfigMetadata(fields MetadataField) ConfigOption {
+	return func(c *Config) {
+		c.Metadata = fields
+	}
+}
+
+// ConfigRedact adds keys of the fields whose values are replaced by Redacted wherever the fields of the
+// errors are exposed, such as Detail.Fields, JSON, log/slog and Render, and in the messages rendered from
+// the template of a Definition.
+func ConfigRedact(keys ...string) ConfigOption {
+	return func(c *Config) {
+		c.RedactKeys = append(slices.Clip(c.RedactKeys), keys...)
+	}
+}
+
+// ConfigRedactor sets a function that returns the value exposed in place of each field of the errors, for
+// redaction policies that depend on the values, such as masking card numbers.
+func ConfigRedactor(redactor func(key string, value any) any) ConfigOption {
+	return func(c *Config) {
+		c.Redactor = redactor
+	}
+}
+
+// ConfigReset restores every option to its default, which is the one set by the environment, if any. It
+// is given to Configure before the options that replace the current ones, e.g. to clear the redaction
+// policy, since ConfigRedact adds keys to the ones already set.
+func ConfigReset() ConfigOption {
+	return func(c *Config) {
+		*c = envConfig()
+	}
+}
+
+var (
+	// config holds the options in effect, which are the environment defaults changed by the options
+	// given to Configure.
+	config   atomic.Pointer[Config]
+	configMu sync.Mutex
+)
+
+// Configure changes the global options of the package, which affect the errors created from now on and,
+// for the format and the redaction policy, the errors already created. Options given to Configure are
+// kept across calls, so later calls only change the options they are given, and ConfigReset restores
+// the defaults.
+//
+// The environment variables read when the program starts set the defaults of the options, so the
+// behavior of a program can be changed without a new build for every option it does not set itself. An
+// option given to Configure overrides the environment, and the keys of ERRORS_REDACT are added to the
+// ones given to ConfigRedact:
+//   - ERRORS_STACK: "full", "pcs" or "off".
+//   - ERRORS_STACK_DEPTH: The maximum number of frames.
+//   - ERRORS_PATH: "short", "base" or "full".
+//   - ERRORS_FUNC: "short", "qualified" or "full".
+//   - ERRORS_FORMAT: "full", "cause" or "message".
+//   - ERRORS_METADATA: "none", "all" or a comma-separated list of "build", "go", "host", "pid" and
+//     "goroutine".
+//   - ERRORS_REDACT: A comma-separated list of field keys to be redacted.
+//
+// Invalid values are ignored. Configure is safe to be called concurrently, but it is usually called once
+// during the program initialization.
+//
+// Parameters:
+//   - opts: ConfigOption values, such as ConfigStack.
+//
+// Example:
+//
+//	func main() {
+//		errors.Configure(
+//			errors.ConfigStack(errors.StackPCs),
+//			errors.ConfigMetadata(errors.MetadataBuild|errors.MetadataHost),
+//			errors.ConfigRedact("password", "token"),
+//		)
+//		// ...
+//	}
+func Configure(opts ...ConfigOption) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	var c Config
+	if current := config.Load(); current != nil {
+		c = *current
+	} else {
+		c = envConfig()
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	config.Store(&c)
+}
+
+// CurrentConfig returns the global options of the package, including the environment defaults.
+//
+// Returns:
+//   - Config: A copy of the options.
+func CurrentConfig() Config {
+	c := *loadConfig()
+	c.RedactKeys = slices.Clone(c.RedactKeys)
+	return c
+}
+
+// loadConfig returns the global options, applying the environment defaults on the first call.
+func loadConfig() *Config {
+	if c := config.Load(); c != nil {
+		return c
+	}
+	Configure()
+	return config.Load()
+}
+
+// envConfig returns the default options, which are the ones set by the environment variables.
+func envConfig() Config {
+	var c Config
+	for _, opt := range envOptions() {
+		opt(&c)
+	}
+	return c
+}
+
+// envOptions returns the options read from the environment variables, which are read only once.
+var envOptions = sync.OnceValue(func() []ConfigOption {
+	return parseEnv(os.Getenv)
+})
+
+// parseEnv returns the options set by the environment variables read by getenv, skipping invalid values.
+func parseEnv(getenv func(key string) string) []ConfigOption {
+	var opts []ConfigOption
+	env := func(key string, fn func(value string) (ConfigOption, bool)) {
+		if value := strings.ToLower(strings.TrimSpace(getenv(key))); value != "" {
+			if opt, ok := fn(value); ok {
+				opts = append(opts, opt)
+			}
+		}
+	}
+
+	env("ERRORS_STACK", func(value string) (ConfigOption, bool) {
+		mode, ok := map[string]StackCapture{"full": StackFull, "on": StackFull, "pcs": StackPCs, "off": StackOff}[value]
+		return ConfigStack(mode), ok
+	})
+	env("ERRORS_STACK_DEPTH", func(value string) (ConfigOption, bool) {
+		depth, err := strconv.Atoi(value)
+		return ConfigStackDepth(depth), err == nil && depth >= 0
+	})
+	env("ERRORS_PATH", func(value string) (ConfigOption, bool) {
+		mode, ok := map[string]PathMode{"short": PathShort, "base": PathBase, "full": PathFull}[value]
+		return ConfigPaths(mode), ok
+	})
+	env("ERRORS_FUNC", func(value string) (ConfigOption, bool) {
+		mode, ok := map[string]FuncMode{"short": FuncShort, "qualified": FuncQualified, "full": FuncFull}[value]
+		return ConfigFuncNames(mode), ok
+	})
+	env("ERRORS_FORMAT", func(value string) (ConfigOption, bool) {
+		format, ok := map[string]ErrorFormat{
+			"full": ErrorFormatFull, "cause": ErrorFormatCause, "message": ErrorFormatMessage,
+		}[value]
+		return ConfigFormat(format), ok
+	})
+	env("ERRORS_METADATA", func(value string) (ConfigOption, bool) {
+		names := map[string]MetadataField{
+			"none": MetadataNone, "all": MetadataAll, "build": MetadataBuild, "go": MetadataGoVersion,
+			"host": MetadataHost, "pid": MetadataPID, "goroutine": MetadataGoroutine,
+		}
+		var fields MetadataField
+		for _, name := range strings.Split(value, ",") {
+			field, ok := names[strings.TrimSpace(name)]
+			if !ok {
+				return nil, false
+			}
+			fields |= field
+		}
+		return ConfigMetadata(fields), true
+	})
+	if value := getenv("ERRORS_REDACT"); value != "" {
+		var keys []string
+		for _, key := range strings.Split(value, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				keys = append(keys, key)
+			}
+		}
+		opts = append(opts, ConfigRedact(keys...))
+	}
+	return opts
+}
+
+// redact returns the value of the field exposed according to the redaction policy.
+func (c *Config) redact(key string, value any) any {
+	for _, k := range c.RedactKeys {
+		if strings.EqualFold(k, key) {
+			return Redacted
+		}
+	}
+	if c.Redactor != nil {
+		return c.Redactor(key, value)
+	}
+	return value
+}
+
+// formatFile returns the file of an origin according to the PathMode.
+func (c *Config) formatFile(file string) string {
+	switch c.Paths {
+	case PathBase:
+		return filepath.Base(file)
+	case PathFull:
+		return filepath.ToSlash(file)
+	default:
+		dir, base := filepath.Split(file)
+		return filepath.Base(dir) + "/" + base
+	}
+}
+
+// formatFunc returns the function of an origin according to the FuncMode.
+func (c *Config) formatFunc(name string) string {
+	switch c.FuncNames {
+	case FuncQualified:
+		return path.Base(name)
+	case FuncFull:
+		return name
+	default:
+		return formatFuncName(name)
+	}
+}
@@ -0,0 +1,261 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// setConfig configures the package for a test, restoring the previous options when it finishes.
+func setConfig(t *testing.T, opts ...ConfigOption) {
+	previous := config.Load()
+	t.Cleanup(func() { config.Store(previous) })
+	Configure(opts...)
+}
+
+func TestConfigureOrigin(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+	tests := []struct {
+		name     string
+		opts     []ConfigOption
+		wantFile string
+		wantFunc string
+	}{
+		{"Default", nil, filepath.Base(filepath.Dir(file)) + "/config_test.go", "func1"},
+		{"Base path", []ConfigOption{ConfigPaths(PathBase)}, "config_test.go", "func1"},
+		{"Full path", []ConfigOption{ConfigPaths(PathFull)}, file, "func1"},
+		{"Qualified function", []ConfigOption{ConfigFuncNames(FuncQualified)}, "", "errors.TestConfigureOrigin.func1"},
+		{"Full function", []ConfigOption{ConfigFuncNames(FuncFull)}, "", "github.com/tech4works/errors.TestConfigureOrigin.func1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setConfig(t, tt.opts...)
+
+			detail := Details(New("test error"))
+			if tt.wantFile != "" && detail.File() != tt.wantFile {
+				t.Errorf("Detail.File() = %v, want %v", detail.File(), tt.wantFile)
+			}
+			if detail.Func() != tt.wantFunc {
+				t.Errorf("Detail.Func() = %v, want %v", detail.Func(), tt.wantFunc)
+			}
+			if frames := detail.Frames(); len(frames) == 0 || frames[0].Line != detail.Line() {
+				t.Errorf("Detail.Frames() = %v", frames)
+			}
+			if parsed := Details(errors.New(detail.Error())); parsed.File() != detail.File() || parsed.Func() != detail.Func() {
+				t.Errorf("Details() = %v, want %v", parsed.Cause(), detail.Cause())
+			}
+		})
+	}
+}
+
+func TestConfigureStack(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []ConfigOption
+		wantEmpty bool
+		maxFrames int
+	}{
+		{"Stack off", []ConfigOption{ConfigStack(StackOff)}, true, 0},
+		{"Full stack depth", []ConfigOption{ConfigStackDepth(2)}, false, 2},
+		{"PC stack depth", []ConfigOption{ConfigStack(StackPCs), ConfigStackDepth(2)}, false, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setConfig(t, tt.opts...)
+
+			detail := Details(New("test error"))
+			if (detail.Stack() == "") != tt.wantEmpty {
+				t.Fatalf("Detail.Stack() = %q", detail.Stack())
+			}
+			frames := detail.Frames()
+			if len(frames) > tt.maxFrames {
+				t.Errorf("Detail.Frames() = %v, want at most %d frames", frames, tt.maxFrames)
+			}
+			if len(frames) > 0 && frames[0].Line != detail.Line() {
+				t.Errorf("Detail.Frames()[0] = %v, want the origin of the error", frames[0])
+			}
+			if !IsDetailed(errors.New(detail.Error())) {
+				t.Errorf("IsDetailed(%q) = false", detail.Error())
+			}
+		})
+	}
+}
+
+func TestTruncateStack(t *testing.T) {
+	stack := "goroutine 7 [running]:\n" +
+		"main.c()\n\t/app/main.go:30 +0x1\n" +
+		"main.b()\n\t/app/main.go:20 +0x1\n" +
+		"main.a()\n\t/app/main.go:10 +0x1\n" +
+		"created by main.main in goroutine 1\n\t/app/main.go:5 +0x1\n"
+
+	got := truncateStack(stack, 1)
+	want := "goroutine 7 [running]:\n" +
+		"main.c()\n\t/app/main.go:30 +0x1\n" +
+		"...additional frames elided...\n" +
+		"created by main.main in goroutine 1\n\t/app/main.go:5 +0x1\n"
+	if got != want {
+		t.Errorf("truncateStack() = %q, want %q", got, want)
+	}
+	if got := truncateStack(stack, 3); got != stack {
+		t.Errorf("truncateStack() = %q, want %q", got, stack)
+	}
+}
+
+func TestConfigureFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		format ErrorFormat
+		want   func(detail *Detail) string
+	}{
+		{"Full", ErrorFormatFull, func(d *Detail) string { return "[CAUSE]: " + d.Cause() + " [STACK]: " + d.Stack() }},
+		{"Cause", ErrorFormatCause, (*Detail).Cause},
+		{"Message", ErrorFormatMessage, (*Detail).Message},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setConfig(t, ConfigFormat(tt.format))
+
+			err := New("test error")
+			detail := Details(err)
+			if got, want := detail.Error(), tt.want(detail); got != want {
+				t.Errorf("Detail.Error() = %q, want %q", got, want)
+			}
+
+			wrapped := fmt.Errorf("handling request: %w", err)
+			if !IsDetailed(err) || !IsDetailed(wrapped) {
+				t.Error("IsDetailed() should recognize a *Detail under every format")
+			}
+			if Details(wrapped) != detail {
+				t.Errorf("Details() = %v, want the wrapped *Detail", Details(wrapped))
+			}
+			if got := MessageOf(wrapped); got != "handling request: test error" {
+				t.Errorf("MessageOf() = %q, want %q", got, "handling request: test error")
+			}
+			parsed := errors.New(detail.Error())
+			if IsDetailed(parsed) != (tt.format == ErrorFormatFull) {
+				t.Errorf("IsDetailed() = %v for the text of the error, want %v", IsDetailed(parsed), tt.format == ErrorFormatFull)
+			}
+		})
+	}
+}
+
+func TestConfigurePrecedence(t *testing.T) {
+	previous := envOptions
+	t.Cleanup(func() { envOptions = previous })
+	envOptions = func() []ConfigOption {
+		return parseEnv(func(key string) string {
+			return map[string]string{"ERRORS_PATH": "base", "ERRORS_FUNC": "full", "ERRORS_REDACT": "token"}[key]
+		})
+	}
+
+	setConfig(t, ConfigReset(), ConfigPaths(PathFull), ConfigRedact("password"))
+	c := CurrentConfig()
+	if c.Paths != PathFull {
+		t.Errorf("Config.Paths = %v, want the option given to Configure", c.Paths)
+	}
+	if c.FuncNames != FuncFull {
+		t.Errorf("Config.FuncNames = %v, want the environment default", c.FuncNames)
+	}
+	if !reflect.DeepEqual(c.RedactKeys, []string{"token", "password"}) {
+		t.Errorf("Config.RedactKeys = %v, want the keys of both", c.RedactKeys)
+	}
+
+	Configure(ConfigFuncNames(FuncShort))
+	if c = CurrentConfig(); c.Paths != PathFull || c.FuncNames != FuncShort {
+		t.Errorf("Configure() = %+v, want the options of every call", c)
+	}
+
+	Configure(ConfigRedactor(func(string, any) any { return nil }), ConfigFormat(ErrorFormatMessage))
+	Configure(ConfigReset(), ConfigRedact("secret"))
+	c = CurrentConfig()
+	if c.Paths != PathBase || c.FuncNames != FuncFull || c.Format != ErrorFormatFull || c.Redactor != nil {
+		t.Errorf("ConfigReset() = %+v, want the environment defaults", c)
+	}
+	if !reflect.DeepEqual(c.RedactKeys, []string{"token", "secret"}) {
+		t.Errorf("Config.RedactKeys = %v, want the keys of the environment and of the last call", c.RedactKeys)
+	}
+}
+
+func TestConfigureRedact(t *testing.T) {
+	setConfig(t, ConfigRedact("Password"), ConfigRedactor(func(key string, value any) any {
+		if key == "card" {
+			s := value.(string)
+			return strings.Repeat("*", len(s)-4) + s[len(s)-4:]
+		}
+		return value
+	}))
+
+	def := Define("AUTH_FAILED", Unauthenticated, "login failed for {user} with {password} and {card}", Params("user", "password", "card"))
+	err := def.New("ana", "secret", "4111111111111111")
+
+	want := Fields{"user": "ana", "password": Redacted, "card": "************1111"}
+	if got := Details(err).Fields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Detail.Fields() = %v, want %v", got, want)
+	}
+	if bs, _ := Details(err).MarshalJSON(); strings.Contains(string(bs), "secret") || strings.Contains(string(bs), "4111111111111111") {
+		t.Errorf("Detail.MarshalJSON() = %s", bs)
+	}
+	if report := Render(err, RenderColors(false), RenderSourceLines(-1)); strings.Contains(report, "secret") ||
+		!strings.Contains(report, "error: login failed for ana with [REDACTED] and ************1111\n") {
+		t.Errorf("Render() = %s", report)
+	}
+}
+
+func TestParseEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Config
+	}{
+		{"Empty", nil, Config{}},
+		{
+			"Valid values",
+			map[string]string{
+				"ERRORS_STACK":       "OFF",
+				"ERRORS_STACK_DEPTH": "16",
+				"ERRORS_PATH":        "full",
+				"ERRORS_FUNC":        "qualified",
+				"ERRORS_FORMAT":      "message",
+				"ERRORS_METADATA":    "build, host",
+				"ERRORS_REDACT":      "password, token",
+			},
+			Config{
+				Stack:      StackOff,
+				StackDepth: 16,
+				Paths:      PathFull,
+				FuncNames:  FuncQualified,
+				Format:     ErrorFormatMessage,
+				Metadata:   MetadataBuild | MetadataHost,
+				RedactKeys: []string{"password", "token"},
+			},
+		},
+		{
+			"Invalid values",
+			map[string]string{
+				"ERRORS_STACK":       "sometimes",
+				"ERRORS_STACK_DEPTH": "-1",
+				"ERRORS_METADATA":    "build,disk",
+			},
+			Config{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Config
+			for _, opt := range parseEnv(func(key string) string { return tt.env[key] }) {
+				opt(&got)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseEnv() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package errors
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// ContextExtractor extracts request-scoped fields, such as a request ID, a tenant or a trace ID, from a
+// context. The fields returned by the registered extractors are recorded on the errors created by the
+// context-aware constructors.
+type ContextExtractor func(ctx context.Context) Fields
+
+var contextExtractors struct {
+	sync.RWMutex
+	list []*ContextExtractor
+}
+
+// RegisterContextExtractor registers an extractor used by NewCtx, NewCtxf, WrapCtx and
+// Definition.NewCtx. It is meant to be called during the program initialization; extractors are
+// executed in the order they were registered and later extractors override the fields of earlier ones.
+// The returned function unregisters the extractor, which is mostly useful in tests.
+//
+// Parameters:
+//   - extractor: The ContextExtractor to be registered.
+//
+// Returns:
+//   - func(): The function that unregisters the extractor.
+//
+// Example:
+//
+//	type requestIDKey struct{}
+//
+//	RegisterContextExtractor(ContextValueExtractor(requestIDKey{}, "request_id"))
+//
+//	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-123")
+//	err := NewCtx(ctx, "payment declined")
+//	fmt.Println(Details(err).Fields()) // map[request_id:req-123]
+func RegisterContextExtractor(extractor ContextExtractor) func() {
+	contextExtractors.Lock()
+	defer contextExtractors.Unlock()
+
+	entry := &extractor
+	contextExtractors.list = append(contextExtractors.list, entry)
+	return func() {
+		contextExtractors.Lock()
+		defer contextExtractors.Unlock()
+
+		contextExtractors.list = slices.DeleteFunc(slices.Clone(contextExtractors.list), func(e *ContextExtractor) bool {
+			return e == entry
+		})
+	}
+}
+
+// ContextValueExtractor returns a ContextExtractor that records the value stored in the context under
+// key as the given field. Nothing is recorded when the context has no value for key.
+//
+// Parameters:
+//   - key: The key of the context value.
+//   - field: The name of the field recorded on the error.
+//
+// Returns:
+//   - ContextExtractor: The extractor of the context value.
+func ContextValueExtractor(key any, field string) ContextExtractor {
+	return func(ctx context.Context) Fields {
+		value := ctx.Value(key)
+		if value == nil {
+			return nil
+		}
+		return Fields{field: value}
+	}
+}
+
+// NewCtx works like New, and also records on the error the fields returned by the registered
+// ContextExtractor values for ctx.
+//
+// Parameters:
+//   - ctx: The request-scoped context.
+//   - args: Variadic arguments of any type to be composed into an error message.
+//
+// Returns:
+//   - error: A *Detail with the request-scoped fields of ctx.
+func NewCtx(ctx context.Context, args ...any) error {
+	detail := newDetail(1, buildMessage(args...))
+	detail.addContextFields(ctx)
+	return detail
+}
+
+// NewCtxf works like Newf, and also records on the error the fields returned by the registered
+// ContextExtractor values for ctx.
+//
+// Parameters:
+//   - ctx: The request-scoped context.
+//   - format: A format as a string.
+//   - args: Variadic arguments of any type to be composed into an error message following the format.
+//
+// Returns:
+//   - error: A *Detail with the request-scoped fields of ctx.
+func NewCtxf(ctx context.Context, format string, args ...any) error {
+	detail := newDetail(1, buildMessageByFormat(format, args...))
+	detail.addContextFields(ctx)
+	return detail
+}
+
+// WrapCtx works like Wrap, and also records on the error the fields returned by the registered
+// ContextExtractor values for ctx.
+//
+// Parameters:
+//   - ctx: The request-scoped context.
+//   - err: The error to be wrapped.
+//   - args: Variadic arguments of any type to be composed into the wrap message.
+//
+// Returns:
+//   - error: A *Detail wrapping err with the request-scoped fields of ctx, or nil when err is nil.
+func WrapCtx(ctx context.Context, err error, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	detail := wrap(1, err, buildMessage(args...))
+	detail.addContextFields(ctx)
+	return detail
+}
+
+// NewCtx works like Definition.New, and also records on the error the fields returned by the registered
+// ContextExtractor values for ctx. The fields of the template take precedence over the context fields.
+//
+// Parameters:
+//   - ctx: The request-scoped context.
+//   - args: A single Fields value or variadic arguments used to fill the template placeholders.
+//
+// Returns:
+//   - error: A *Detail carrying the code and kind of the Definition and the request-scoped fields of ctx.
+func (d *Definition) NewCtx(ctx context.Context, args ...any) error {
+	detail := d.newDetail(1, args...)
+	detail.addContextFields(ctx)
+	return detail
+}
+
+// addContextFields records the fields extracted from ctx apart from the template placeholders, on top of
+// the context fields inherited from a wrapped error, and the profiler labels of ctx.
+func (e *Detail) addContextFields(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+	e.labels = goroutineLabels(ctx)
+
+	contextExtractors.RLock()
+	extracted := Fields{}
+	for _, extractor := range contextExtractors.list {
+		for k, v := range (*extractor)(ctx) {
+			extracted[k] = v
+		}
+	}
+	contextExtractors.RUnlock()
+
+	if len(extracted) == 0 {
+		return
+	}
+	fields := maps.Clone(e.contextFields)
+	if fields == nil {
+		fields = Fields{}
+	}
+	maps.Copy(fields, extracted)
+	e.contextFields = fields
+}
@@ -0,0 +1,100 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type testRequestIDKey struct{}
+
+type testTenantKey struct{}
+
+// registerTestExtractors registers the extractors of the test keys until the test finishes.
+func registerTestExtractors(t *testing.T) {
+	t.Cleanup(RegisterContextExtractor(ContextValueExtractor(testRequestIDKey{}, "request_id")))
+	t.Cleanup(RegisterContextExtractor(func(ctx context.Context) Fields {
+		if tenant, ok := ctx.Value(testTenantKey{}).(string); ok {
+			return Fields{"tenant": tenant, "id": "from context"}
+		}
+		return nil
+	}))
+}
+
+func TestNewCtx(t *testing.T) {
+	registerTestExtractors(t)
+	ctx := context.WithValue(context.Background(), testRequestIDKey{}, "req-123")
+	ctx = context.WithValue(ctx, testTenantKey{}, "acme")
+
+	tests := []struct {
+		name       string
+		err        error
+		wantFields Fields
+	}{
+		{"NewCtx", NewCtx(ctx, "payment declined"), Fields{"request_id": "req-123", "tenant": "acme", "id": "from context"}},
+		{"NewCtxf", NewCtxf(ctx, "payment %s", "declined"), Fields{"request_id": "req-123", "tenant": "acme", "id": "from context"}},
+		{"WrapCtx", WrapCtx(ctx, errors.New("declined"), "payment"), Fields{"request_id": "req-123", "tenant": "acme", "id": "from context"}},
+		{"Definition.NewCtx", errTestWrapNotFound.NewCtx(ctx, 42), Fields{"request_id": "req-123", "tenant": "acme", "id": 42}},
+		{"Context without values", NewCtx(context.Background(), "payment declined"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detail := Details(tt.err)
+			fields := detail.Fields()
+			if len(fields) != len(tt.wantFields) {
+				t.Fatalf("Detail.Fields() = %v, want %v", fields, tt.wantFields)
+			}
+			for k, v := range tt.wantFields {
+				if fields[k] != v {
+					t.Errorf("Detail.Fields()[%q] = %v, want %v", k, fields[k], v)
+				}
+			}
+			if detail.Func() != "TestNewCtx" {
+				t.Errorf("Detail.Func() = %v, want TestNewCtx", detail.Func())
+			}
+		})
+	}
+}
+
+func TestRegisterContextExtractorUnregister(t *testing.T) {
+	unregister := RegisterContextExtractor(ContextValueExtractor(testRequestIDKey{}, "request_id"))
+	ctx := context.WithValue(context.Background(), testRequestIDKey{}, "req-123")
+	if fields := Details(NewCtx(ctx, "failed")).Fields(); fields["request_id"] != "req-123" {
+		t.Fatalf("NewCtx() fields = %v", fields)
+	}
+
+	unregister()
+	if fields := Details(NewCtx(ctx, "failed")).Fields(); fields != nil {
+		t.Errorf("NewCtx() fields after unregistering = %v, want nil", fields)
+	}
+}
+
+func TestWrapCtxNil(t *testing.T) {
+	if WrapCtx(context.Background(), nil, "message") != nil {
+		t.Error("WrapCtx() should return nil for nil errors")
+	}
+}
+
+func TestNewCtxProblem(t *testing.T) {
+	registerTestExtractors(t)
+	ctx := context.WithValue(context.Background(), testRequestIDKey{}, "req-123")
+	err := errTestWrapNotFound.NewCtx(ctx, 42)
+
+	if got := ToProblem(err).Params; len(got) != 1 || got["id"] != 42 {
+		t.Errorf("ToProblem() Params = %v, want only the template params", got)
+	}
+	if got := Details(err).ContextFields(); len(got) != 1 || got["request_id"] != "req-123" {
+		t.Errorf("Detail.ContextFields() = %v", got)
+	}
+
+	bs, _ := json.Marshal(err)
+	var decoded Detail
+	if unmarshalErr := json.Unmarshal(bs, &decoded); unmarshalErr != nil {
+		t.Fatal(unmarshalErr)
+	}
+	if got := decoded.ContextFields(); got["request_id"] != "req-123" || decoded.Fields()["id"] == nil {
+		t.Errorf("decoded Detail.ContextFields() = %v, Fields() = %v", got, decoded.Fields())
+	}
+}
@@ -3,15 +3,17 @@ package errors
 import (
 	"errors"
 	"regexp"
-	"runtime/debug"
+	"slices"
 	"strings"
+	"time"
 )
 
-const regex = `\[CAUSE]: \(([^:]+):(\d+)\) ([^:]+): (.+?) \[STACK]:\s*([\s\S]+)`
+const regex = `\[CAUSE]: \(([^:]+):(\d+)\) ([^:]+): (.+?) \[STACK]:\s*([\s\S]*)`
 
 // Is checks if the target error is the same as the error passed to it. If either err or target
-// is of type Detail, it extracts the message and compares it with the other error. Returns
-// true if err is not nil and the same as target, and false otherwise.
+// is of type Detail, it extracts the message and compares it with the other error. When err was
+// built by Wrap, the errors it wraps are compared as well, so a wrapped error still matches its
+// target. Returns true if err is not nil and the same as target, and false otherwise.
 //
 // Parameters:
 //   - err: The actual error to be checked.
@@ -30,11 +32,12 @@ const regex = `\[CAUSE]: \(([^:]+):(\d+)\) ([^:]+): (.+?) \[STACK]:\s*([\s\S]+)`
 //	targetDetail := New("test error")
 //	fmt.Println(Is(errDetail, targetDetail)) // true
 //
+//	fmt.Println(Is(Wrap(targetDetail, "loading"), targetDetail)) // true
+//
 //	fmt.Println(Is(nil, nil)) // false
 func Is(err, target error) bool {
-	if IsDetailed(err) {
-		errDetails := Details(err)
-		err = errors.New(errDetails.Message())
+	if err == nil || target == nil {
+		return false
 	}
 
 	if IsDetailed(target) {
@@ -42,7 +45,26 @@ func Is(err, target error) bool {
 		target = errors.New(errDetails.Message())
 	}
 
-	return err != nil && target != nil && err.Error() == target.Error()
+	return slices.Contains(chainMessages(err), target.Error())
+}
+
+// chainMessages returns the message of err followed by the messages of the errors it wraps through Wrap.
+// Errors that are not detailed only have their own message.
+func chainMessages(err error) []string {
+	if !IsDetailed(err) {
+		return []string{err.Error()}
+	}
+	detail := Details(err)
+	messages := []string{detail.Message()}
+	for cause := detail.cause; cause != nil; {
+		messages = append(messages, MessageOf(cause))
+		next, ok := cause.(*Detail)
+		if !ok {
+			break
+		}
+		cause = next.cause
+	}
+	return messages
 }
 
 // IsNot checks if the target error is different from the error passed to it.
@@ -73,7 +95,8 @@ func IsNot(err, target error) bool {
 // Contains determines whether the error message from the 'err' error is found
 // within the error message from the 'target' error. It uses the 'IsDetailed' function
 // to check if the errors are detailed, gets their messages using 'Details' function and checks
-// if the error message of 'err' contains that of 'target'.
+// if the error message of 'err' contains that of 'target'. The message of an error built by Wrap
+// includes the messages of the errors it wraps, so they are contained in it.
 //
 // Parameters:
 //   - err: The error to be checked.
@@ -128,14 +151,15 @@ func NotContains(err, target error) bool {
 	return !Contains(err, target)
 }
 
-// IsDetailed checks if a given error matches a detailed error regex. If the error is not nil and it matches
-// the regex pattern regexErrorDetail, it returns true; otherwise, it returns false.
+// IsDetailed checks if a given error is, or wraps, a *Detail, or matches a detailed error regex. If the
+// error is not nil and either holds a *Detail, whatever the format set by ConfigFormat, or its message
+// matches the regex pattern regexErrorDetail, it returns true; otherwise, it returns false.
 //
 // Parameters:
-//   - err: The error to be checked against the detailed error regex.
+//   - err: The error to be checked.
 //
 // Returns:
-//   - bool: A boolean value indicating whether the given error matches the detailed error regex.
+//   - bool: A boolean value indicating whether the given error is detailed.
 //
 // Example:
 //
@@ -145,14 +169,22 @@ func NotContains(err, target error) bool {
 //	err = errors.New("simple error")
 //	fmt.Println(IsDetailed(err)) // false
 func IsDetailed(err error) bool {
+	if err == nil {
+		return false
+	}
+	var detail *Detail
+	if errors.As(err, &detail) {
+		return true
+	}
 	regex := regexp.MustCompile(regex)
-	return err != nil && regex.MatchString(err.Error())
+	return regex.MatchString(err.Error())
 }
 
 // Details function extracts detailed information from an error
 //
 // This function works by extracting specific parts of the error message using regular expressions.
 // The details extracted include the file name, line number, function name, the error message, and a debug stack trace.
+// When err is, or wraps, a *Detail, that Detail is returned as is, preserving its code and kind.
 // If the error does not match the expected format, the function uses runtime.Caller and debug.Stack to get the
 // file info and debug stack, and creates an error message using buildMessage function. It then creates a new Detail object
 // with these details and returns it. If the provided error is nil, function simply returns nil.
@@ -182,11 +214,19 @@ func Details(err error) *Detail {
 		return nil
 	}
 
+	var detail *Detail
+	if errors.As(err, &detail) {
+		return detail
+	}
+
 	var file string
 	var line string
 	var funcName string
 	var message string
 	var stack string
+	var pcs []uintptr
+	var metadata *Metadata
+	var created time.Time
 
 	rg := regexp.MustCompile(regex)
 	matches := rg.FindStringSubmatch(err.Error())
@@ -199,7 +239,9 @@ func Details(err error) *Detail {
 		stack = matches[5]
 	} else {
 		file, line, funcName = callerInfos(2)
-		stack = string(debug.Stack())
+		stack, pcs = captureStack(2)
+		metadata = captureMetadata(stack)
+		created = time.Now()
 		message = buildMessage(err.Error())
 	}
 
@@ -209,17 +251,39 @@ func Details(err error) *Detail {
 		funcName: funcName,
 		message:  message,
 		stack:    stack,
+		pcs:      pcs,
+		metadata: metadata,
+		time:     created,
 	}
 }
 
+// Join returns an error whose message is the messages of errs joined by sep, as returned by JoinToString.
+// The returned error is flat: it does not wrap errs, so Is, Contains, Walk and the other functions that
+// inspect the chain do not see them. To keep the joined errors in the chain, use the standard library
+// errors.Join instead.
+//
+// Parameters:
+//   - errs: The errors to be joined.
+//   - sep: The separator placed between the messages.
+//
+// Returns:
+//   - error: An error with the joined messages.
 func Join(errs []error, sep string) error {
 	return errors.New(JoinToString(errs, sep))
 }
 
+// JoinToString returns the messages of errs, without their origin and stack, joined by sep.
+//
+// Parameters:
+//   - errs: The errors whose messages are joined.
+//   - sep: The separator placed between the messages.
+//
+// Returns:
+//   - string: The joined messages.
 func JoinToString(errs []error, sep string) (result string) {
 	for i, err := range errs {
 		dt := Details(err)
-		result += dt.message
+		result += dt.Message()
 		if i < len(errs)-1 {
 			result += sep
 		}
@@ -17,6 +17,12 @@ func TestIs(t *testing.T) {
 		{"Both are nil", nil, nil, false},
 		{"Errors are the same", New("test error"), New("test error"), true},
 		{"Errors are different", New("test error 1"), New("test error 2"), false},
+		{"Wrapped error", Wrap(New("test error"), "loading"), New("test error"), true},
+		{"Error wrapped twice", Wrap(Wrap(New("test error"), "loading"), "handling"), New("test error"), true},
+		{"Wrapping error", Wrap(New("test error"), "loading"), New("loading: test error"), true},
+		{"Wrapped standard error", Wrap(errors.New("EOF"), "reading"), errors.New("EOF"), true},
+		{"Wrap message alone", Wrap(New("test error"), "loading"), New("loading"), false},
+		{"Target wraps the error", New("test error"), Wrap(New("test error"), "loading"), false},
 	}
 
 	for _, tt := range tests {
@@ -60,6 +66,9 @@ func TestContains(t *testing.T) {
 	}{
 		{"Error is not nil and target is contained", New("test error target"), New("target"), true},
 		{"Error is not nil and target is not contained", New("test error"), New("target"), false},
+		{"Wrapped error is contained", Wrap(New("test error"), "loading"), New("test error"), true},
+		{"Wrap message is contained", Wrap(New("test error"), "loading"), New("loading"), true},
+		{"Wrapping error is not contained in the wrapped one", New("test error"), Wrap(New("test error"), "loading"), false},
 		{"Error is nil", nil, New("target"), false},
 		{"Target is nil", New("test error"), nil, false},
 		{"Both are nil", nil, nil, false},
@@ -0,0 +1,79 @@
+package debugerrors
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>/debug/errors</title>
+<style>
+body { font-family: sans-serif; font-size: 14px; margin: 1em 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border-bottom: 1px solid #ddd; padding: 4px 8px; text-align: left; vertical-align: top; }
+pre { margin: 4px 0; font-size: 12px; }
+code, .mono { font-family: monospace; }
+</style>
+</head>
+<body>
+<h1>/debug/errors</h1>
+<p>{{ len .Groups }} groups, {{ len .Entries }} recent errors. <a href="?format=json">JSON</a></p>
+<h2>Groups</h2>
+<table>
+<tr><th>Count</th><th>Code</th><th>Message</th><th>Origin</th><th>First seen</th><th>Last seen</th><th>Fingerprint</th></tr>
+{{- range .Groups }}
+<tr>
+<td>{{ .Count }}</td>
+<td class="mono">{{ .Code }}</td>
+<td>{{ .Message }}</td>
+<td class="mono">{{ .File }}:{{ .Line }} {{ .Func }}</td>
+<td>{{ .FirstSeen.Format "2006-01-02 15:04:05.000" }}</td>
+<td>{{ .LastSeen.Format "2006-01-02 15:04:05.000" }}</td>
+<td class="mono">{{ .Fingerprint }}</td>
+</tr>
+{{- end }}
+</table>
+<h2>Recent errors</h2>
+<table>
+<tr><th>Time</th><th>Error</th></tr>
+{{- range .Entries }}
+<tr>
+<td>{{ .Time.Format "2006-01-02 15:04:05.000" }}</td>
+<td>
+<details>
+<summary><code>{{ .Error.Cause }}</code></summary>
+<pre>{{ printf "%+v" .Error }}</pre>
+</details>
+</td>
+</tr>
+{{- end }}
+</table>
+</body>
+</html>
+`))
+
+// ServeHTTP serves the groups and the recent errors kept by the Recorder as an HTML page or, when the
+// "format" query parameter is "json" or the request accepts "application/json", as a JSON document with
+// the "groups" and "entries" properties.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	data := struct {
+		Groups  []Group `json:"groups"`
+		Entries []Entry `json:"entries"`
+	}{r.Groups(), r.Entries()}
+
+	if req.URL.Query().Get("format") == "json" || strings.Contains(req.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pageTemplate.Execute(w, data)
+}
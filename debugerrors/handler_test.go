@@ -0,0 +1,61 @@
+package debugerrors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tech4works/errors"
+)
+
+func TestRecorder_ServeHTTP(t *testing.T) {
+	recorder := NewRecorder(10)
+	recorder.Report(errors.WithHint(errors.New("<script>boom</script>"), "retry"))
+
+	tests := []struct {
+		name        string
+		target      string
+		accept      string
+		contentType string
+	}{
+		{"HTML", "/debug/errors", "", "text/html; charset=utf-8"},
+		{"JSON by query", "/debug/errors?format=json", "", "application/json"},
+		{"JSON by header", "/debug/errors", "application/json", "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+			recorder.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Fatalf("Content-Type = %v, want %v", got, tt.contentType)
+			}
+
+			body := rec.Body.String()
+			if tt.contentType == "application/json" {
+				var data struct {
+					Groups  []Group `json:"groups"`
+					Entries []struct {
+						Error struct {
+							Message string   `json:"message"`
+							Hints   []string `json:"hints"`
+						} `json:"error"`
+					} `json:"entries"`
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), &data); err != nil {
+					t.Fatalf("invalid JSON %s: %v", body, err)
+				}
+				if len(data.Groups) != 1 || len(data.Entries) != 1 || data.Entries[0].Error.Hints[0] != "retry" {
+					t.Errorf("ServeHTTP() = %s", body)
+				}
+			} else if strings.Contains(body, "<script>") || !strings.Contains(body, "&lt;script&gt;boom") {
+				t.Errorf("ServeHTTP() should escape the error messages, got %s", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+// Package debugerrors keeps the most recent errors of the process in memory and serves them over HTTP,
+// similar to net/http/pprof, so the errors of a single instance can be inspected without a centralized
+// log or error tracker.
+//
+// The recorder is opt-in. The simplest setup registers it as an errors.Reporter and mounts its handler
+// at /debug/errors on http.DefaultServeMux:
+//
+//	debugerrors.Install(100)
+//
+//	if err := process(); err != nil {
+//		errors.Report(err)
+//	}
+package debugerrors
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tech4works/errors"
+)
+
+// Entry is an error kept by a Recorder.
+type Entry struct {
+	Time        time.Time      `json:"time"`
+	Fingerprint string         `json:"fingerprint"`
+	Error       *errors.Detail `json:"error"`
+}
+
+// Group aggregates the errors with the same fingerprint seen by a Recorder.
+type Group struct {
+	Fingerprint string    `json:"fingerprint"`
+	Code        string    `json:"code,omitempty"`
+	Message     string    `json:"message"`
+	Func        string    `json:"func"`
+	File        string    `json:"file"`
+	Line        int       `json:"line"`
+	Count       int       `json:"count"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+// Recorder keeps the last errors it receives in a ring buffer and counts the errors of every
+// fingerprint. It is safe for concurrent use and implements errors.Reporter and http.Handler.
+type Recorder struct {
+	mu        sync.Mutex
+	entries   []Entry
+	next      int
+	full      bool
+	groups    map[string]*Group
+	maxGroups int
+	now       func() time.Time
+}
+
+// NewRecorder creates a Recorder that keeps the last size errors and the counts of, at most, 10 times
+// size fingerprints. When this limit is reached, the least recently seen fingerprint is discarded.
+//
+// Parameters:
+//   - size: The number of errors kept by the Recorder.
+//
+// Returns:
+//   - *Recorder: The Recorder.
+func NewRecorder(size int) *Recorder {
+	if size < 1 {
+		size = 1
+	}
+	return &Recorder{
+		entries:   make([]Entry, size),
+		groups:    map[string]*Group{},
+		maxGroups: size * 10,
+		now:       time.Now,
+	}
+}
+
+// Install creates a Recorder of the given size, registers it with errors.RegisterReporter and mounts it
+// at /debug/errors on http.DefaultServeMux.
+//
+// Parameters:
+//   - size: The number of errors kept by the Recorder.
+//
+// Returns:
+//   - *Recorder: The installed Recorder.
+func Install(size int) *Recorder {
+	recorder := NewRecorder(size)
+	errors.RegisterReporter(recorder)
+	http.Handle("/debug/errors", recorder)
+	return recorder
+}
+
+// Report records err. Nil errors are ignored.
+func (r *Recorder) Report(err error) {
+	if err == nil {
+		return
+	}
+
+	detail := errors.Details(err)
+	fingerprint := errors.Fingerprint(err)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	r.entries[r.next] = Entry{Time: now, Fingerprint: fingerprint, Error: detail}
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+
+	group, ok := r.groups[fingerprint]
+	if !ok {
+		if len(r.groups) >= r.maxGroups {
+			r.evictGroup()
+		}
+		group = &Group{
+			Fingerprint: fingerprint,
+			Code:        detail.Code(),
+			Message:     detail.Message(),
+			Func:        detail.Func(),
+			File:        detail.File(),
+			Line:        detail.Line(),
+			FirstSeen:   now,
+		}
+		r.groups[fingerprint] = group
+	}
+	group.Count++
+	group.LastSeen = now
+}
+
+// Entries returns the errors kept by the Recorder, from the newest to the oldest.
+//
+// Returns:
+//   - []Entry: The recent errors.
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := r.next
+	if r.full {
+		count = len(r.entries)
+	}
+
+	entries := make([]Entry, 0, count)
+	for i := 1; i <= count; i++ {
+		entries = append(entries, r.entries[(r.next-i+len(r.entries))%len(r.entries)])
+	}
+	return entries
+}
+
+// Groups returns the fingerprints seen by the Recorder, from the most recently seen to the least.
+//
+// Returns:
+//   - []Group: The error groups.
+func (r *Recorder) Groups() []Group {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	groups := make([]Group, 0, len(r.groups))
+	for _, group := range r.groups {
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].LastSeen.After(groups[j].LastSeen)
+	})
+	return groups
+}
+
+// Reset discards every error and group kept by the Recorder.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.entries)
+	r.next = 0
+	r.full = false
+	r.groups = map[string]*Group{}
+}
+
+func (r *Recorder) evictGroup() {
+	var oldest *Group
+	for _, group := range r.groups {
+		if oldest == nil || group.LastSeen.Before(oldest.LastSeen) {
+			oldest = group
+		}
+	}
+	if oldest != nil {
+		delete(r.groups, oldest.Fingerprint)
+	}
+}
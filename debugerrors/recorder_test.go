@@ -0,0 +1,62 @@
+package debugerrors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tech4works/errors"
+)
+
+func newTestError(id int) error {
+	return errors.Newf("user %d not found", id)
+}
+
+func TestRecorder_Report(t *testing.T) {
+	recorder := NewRecorder(2)
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	recorder.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+
+	for i := 1; i <= 2; i++ {
+		recorder.Report(newTestError(i))
+	}
+	recorder.Report(errors.New("other error"))
+	recorder.Report(nil)
+
+	entries := recorder.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Recorder.Entries() = %v, want 2 entries", entries)
+	}
+	if entries[0].Error.Message() != "other error" || entries[1].Error.Message() != "user 2 not found" {
+		t.Errorf("Recorder.Entries() should go from the newest to the oldest, got %v, %v",
+			entries[0].Error.Message(), entries[1].Error.Message())
+	}
+
+	groups := recorder.Groups()
+	if len(groups) != 2 {
+		t.Fatalf("Recorder.Groups() = %v, want 2 groups", groups)
+	}
+	if groups[0].Count != 1 || groups[1].Count != 2 || groups[1].Message != "user 1 not found" {
+		t.Errorf("Recorder.Groups() = %+v", groups)
+	}
+	if !groups[1].FirstSeen.Before(groups[1].LastSeen) {
+		t.Errorf("Recorder.Groups() first seen = %v, last seen = %v", groups[1].FirstSeen, groups[1].LastSeen)
+	}
+
+	recorder.Reset()
+	if len(recorder.Entries()) != 0 || len(recorder.Groups()) != 0 {
+		t.Error("Recorder.Reset() should discard everything")
+	}
+}
+
+func TestRecorder_EvictGroup(t *testing.T) {
+	recorder := NewRecorder(1)
+	for i := 0; i < 15; i++ {
+		recorder.Report(errors.WithFingerprint(errors.New("error"), string(rune('a'+i))))
+	}
+	if groups := recorder.Groups(); len(groups) != 10 {
+		t.Errorf("Recorder.Groups() = %d groups, want 10", len(groups))
+	}
+}
@@ -0,0 +1,361 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Definition describes an error declared once, usually as a package level variable, and instantiated
+// wherever it happens. Every Definition is identified by a unique code and is kept in a global registry,
+// which allows looking it up by code and exporting the full error catalog.
+type Definition struct {
+	code     string
+	kind     Kind
+	template string
+	docsURL  string
+	params   []string
+	types    map[string]string
+	parsed   template
+
+	httpStatus    int
+	publicMessage string
+	hints         []string
+}
+
+// DefinitionOption configures optional attributes of a Definition at Define time.
+type DefinitionOption func(d *Definition)
+
+// CatalogEntry is the exported representation of a Definition, as returned by Catalog.
+type CatalogEntry struct {
+	Code     string   `json:"code"`
+	Kind     Kind     `json:"kind"`
+	Template string   `json:"template"`
+	Params   []string `json:"params,omitempty"`
+	DocsURL  string   `json:"docs_url,omitempty"`
+
+	ParamTypes map[string]string `json:"param_types,omitempty"`
+
+	HTTPStatus    int      `json:"http_status"`
+	PublicMessage string   `json:"public_message,omitempty"`
+	Hints         []string `json:"hints,omitempty"`
+}
+
+var registry = struct {
+	sync.RWMutex
+	byCode map[string]*Definition
+	order  []*Definition
+}{byCode: map[string]*Definition{}}
+
+// Define declares a new error Definition and adds it to the global registry. It is meant to be called
+// during package initialization, so an empty or duplicated code causes a panic, surfacing the conflict
+// as soon as the program starts.
+//
+// The template is the message of the errors created by the Definition. Placeholders in the form
+// "{name}" are filled by the arguments given to New and their values are recorded as fields of the
+// error. When the expected params are declared with the Params option, the placeholders of the
+// template are checked against them, so a missing or extra param is reported at startup.
+//
+// Parameters:
+//   - code: The unique code that identifies the error, e.g. "USER_NOT_FOUND".
+//   - kind: The Kind that classifies the error.
+//   - template: The message template of the error.
+//   - opts: Optional DefinitionOption values, such as DocsURL.
+//
+// Returns:
+//   - *Definition: The registered Definition.
+//
+// Panic:
+//   - If code is empty or if another Definition with the same code was already registered.
+//   - If the params declared with Params do not match the placeholders of the template.
+//
+// Example:
+//
+//	var ErrUserNotFound = Define("USER_NOT_FOUND", NotFound, "user {id} not found")
+//
+//	err := ErrUserNotFound.New(42)
+//	fmt.Println(Details(err).Message()) // user 42 not found
+//
+//	var ErrLimitExceeded = Define("LIMIT_EXCEEDED", FailedPrecondition, "order {orderID} exceeds limit {limit}",
+//		Params("orderID", "limit"))
+//
+//	err = ErrLimitExceeded.New(Fields{"orderID": "A1", "limit": 10})
+//	fmt.Println(Details(err).Fields()) // map[limit:10 orderID:A1]
+func Define(code string, kind Kind, template string, opts ...DefinitionOption) *Definition {
+	if code == "" {
+		panic("errors: Define called with an empty code")
+	}
+	d := newDefinition(code, kind, template, opts...)
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, ok := registry.byCode[code]; ok {
+		panic(fmt.Sprintf("errors: duplicate definition for code %q", code))
+	}
+	registry.byCode[code] = d
+	registry.order = append(registry.order, d)
+
+	return d
+}
+
+// newDefinition builds a Definition without adding it to the registry, panicking when its template does
+// not match the declared params.
+func newDefinition(code string, kind Kind, template string, opts ...DefinitionOption) *Definition {
+	d := &Definition{
+		code:     code,
+		kind:     kind,
+		template: template,
+		parsed:   parseTemplate(template),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.params != nil {
+		if err := d.parsed.validate(d.params); err != nil {
+			panic(fmt.Sprintf("errors: invalid template for code %q: %s", code, err))
+		}
+	}
+	return d
+}
+
+// DocsURL sets the documentation URL of a Definition, where users can find details about the error
+// and how to solve it.
+func DocsURL(url string) DefinitionOption {
+	return func(d *Definition) {
+		d.docsURL = url
+	}
+}
+
+// Params declares the named params expected by the template of a Definition. Define panics when the
+// placeholders of the template differ from the declared params.
+func Params(names ...string) DefinitionOption {
+	return func(d *Definition) {
+		d.params = append([]string{}, names...)
+	}
+}
+
+// ParamType declares the Go type of a param of a Definition, e.g. "string" or "int". It does not
+// restrict the values given to New; it documents the param in the exported catalog, which uses it to
+// generate typed clients.
+func ParamType(name, typ string) DefinitionOption {
+	return func(d *Definition) {
+		if d.types == nil {
+			d.types = map[string]string{}
+		}
+		d.types[name] = typ
+	}
+}
+
+// HTTPStatus sets the HTTP status of a Definition, overriding the status derived from its Kind.
+func HTTPStatus(status int) DefinitionOption {
+	return func(d *Definition) {
+		d.httpStatus = status
+	}
+}
+
+// PublicMessage sets a message of a Definition that is safe to be shown to end users, as opposed to
+// the template, which may contain internal details.
+func PublicMessage(msg string) DefinitionOption {
+	return func(d *Definition) {
+		d.publicMessage = msg
+	}
+}
+
+// Hint adds a remediation hint to a Definition, telling what can be done to solve the error. The hints
+// of the Definition are attached to every error created from it.
+func Hint(hint string) DefinitionOption {
+	return func(d *Definition) {
+		d.hints = append(d.hints, hint)
+	}
+}
+
+// Lookup returns the registered Definition for the given code.
+//
+// Parameters:
+//   - code: The code of the Definition.
+//
+// Returns:
+//   - *Definition: The Definition registered with code, or nil when there is none.
+//   - bool: A boolean value indicating whether the Definition was found.
+func Lookup(code string) (*Definition, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	d, ok := registry.byCode[code]
+	return d, ok
+}
+
+// lookup returns the Definition of the code among the registered ones and the ones of the package, which
+// are kept out of the registry so they neither reserve their codes nor show up in the catalog.
+func lookup(code string) (*Definition, bool) {
+	if d, ok := Lookup(code); ok {
+		return d, true
+	}
+	d, ok := builtinDefinitions[code]
+	return d, ok
+}
+
+// Catalog returns every registered Definition as a CatalogEntry, in the order they were defined.
+//
+// Returns:
+//   - []CatalogEntry: The error catalog.
+func Catalog() []CatalogEntry {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	entries := make([]CatalogEntry, 0, len(registry.order))
+	for _, d := range registry.order {
+		entries = append(entries, d.catalogEntry())
+	}
+	return entries
+}
+
+// CatalogJSON returns the error catalog, as returned by Catalog, encoded as indented JSON.
+//
+// Returns:
+//   - []byte: The JSON encoded catalog.
+//   - error: An error if the catalog could not be encoded.
+func CatalogJSON() ([]byte, error) {
+	return json.MarshalIndent(Catalog(), "", "  ")
+}
+
+// New creates a new error from the Definition, capturing the caller information and the debug stack
+// just like the package level New function. A single Fields argument fills the placeholders of the
+// template by name, with its missing and unknown keys flagged in the message as "%!{name}(MISSING)" and
+// "%!(EXTRA key=value)"; otherwise the arguments fill the placeholders in order of appearance and
+// arguments without a matching placeholder are appended to the message. The placeholder values are
+// recorded as fields of the error.
+//
+// Parameters:
+//   - args: A single Fields value or variadic arguments used to fill the template placeholders.
+//
+// Returns:
+//   - error: A *Detail carrying the code and kind of the Definition.
+func (d *Definition) New(args ...any) error {
+	return d.newDetail(1, args...)
+}
+
+// NewSkipCaller works like New, but the caller information is taken skipCaller frames above, following
+// the same convention of the package level NewSkipCaller function.
+//
+// Parameters:
+//   - skipCaller: Integer specifying the number of stack frames to skip.
+//   - args: A single Fields value or variadic arguments used to fill the template placeholders.
+//
+// Returns:
+//   - error: A *Detail carrying the code and kind of the Definition.
+func (d *Definition) NewSkipCaller(skipCaller int, args ...any) error {
+	return d.newDetail(skipCaller, args...)
+}
+
+// Is reports whether err, or any error wrapped by it, was created from the Definition.
+//
+// Parameters:
+//   - err: The error to be checked.
+//
+// Returns:
+//   - bool: A boolean value indicating whether err has the code of the Definition.
+func (d *Definition) Is(err error) bool {
+	return walk(err, func(err error) bool {
+		detail, ok := err.(*Detail)
+		return ok && detail.code == d.code
+	})
+}
+
+// Code returns the unique code of the Definition.
+func (d *Definition) Code() string {
+	return d.code
+}
+
+// Kind returns the Kind of the Definition.
+func (d *Definition) Kind() Kind {
+	return d.kind
+}
+
+// Template returns the message template of the Definition.
+func (d *Definition) Template() string {
+	return d.template
+}
+
+// Params returns the names of the placeholders of the template, in order of appearance.
+func (d *Definition) Params() []string {
+	return append([]string{}, d.parsed.names...)
+}
+
+// DocsURL returns the documentation URL of the Definition.
+func (d *Definition) DocsURL() string {
+	return d.docsURL
+}
+
+// HTTPStatus returns the HTTP status of the Definition, informed with the HTTPStatus option or derived
+// from its Kind.
+func (d *Definition) HTTPStatus() int {
+	if d.httpStatus != 0 {
+		return d.httpStatus
+	}
+	return d.kind.HTTPStatus()
+}
+
+// PublicMessage returns the message of the Definition that is safe to be shown to end users.
+func (d *Definition) PublicMessage() string {
+	return d.publicMessage
+}
+
+// Hints returns the remediation hints of the Definition.
+func (d *Definition) Hints() []string {
+	return slices.Clone(d.hints)
+}
+
+// paramsOf returns the values recorded on the error for the placeholders of the template, leaving out any
+// other field.
+func (d *Definition) paramsOf(detail *Detail) Fields {
+	var params Fields
+	for k, v := range redactFields(detail.fields) {
+		if slices.Contains(d.parsed.names, k) {
+			if params == nil {
+				params = Fields{}
+			}
+			params[k] = v
+		}
+	}
+	return params
+}
+
+func (d *Definition) newDetail(skip int, args ...any) *Detail {
+	msg, fields := d.parsed.render(args...)
+	detail := newDetail(skip+1, msg)
+	detail.code = d.code
+	detail.kind = d.kind
+	detail.fields = fields
+	detail.hints = d.hints
+	detail.docsURL = d.docsURL
+	return detail
+}
+
+func (d *Definition) catalogEntry() CatalogEntry {
+	return CatalogEntry{
+		Code:     d.code,
+		Kind:     d.kind,
+		Template: d.template,
+		Params:   d.Params(),
+		DocsURL:  d.docsURL,
+
+		ParamTypes:    d.paramTypes(),
+		HTTPStatus:    d.HTTPStatus(),
+		PublicMessage: d.publicMessage,
+		Hints:         slices.Clone(d.hints),
+	}
+}
+
+func (d *Definition) paramTypes() map[string]string {
+	if len(d.types) == 0 {
+		return nil
+	}
+	types := make(map[string]string, len(d.types))
+	for name, typ := range d.types {
+		types[name] = typ
+	}
+	return types
+}
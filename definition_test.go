@@ -0,0 +1,124 @@
+package errors
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var errTestDefinitionNotFound = Define("TEST_DEFINITION_NOT_FOUND", NotFound, "user {id} not found",
+	DocsURL("https://docs.example.com/errors/TEST_DEFINITION_NOT_FOUND"))
+
+func TestDefine(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Define() should panic on duplicate codes")
+		}
+	}()
+	Define("TEST_DEFINITION_NOT_FOUND", Internal, "duplicated")
+}
+
+func TestDefineEmptyCode(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Define() should panic on empty codes")
+		}
+	}()
+	Define("", Internal, "empty")
+}
+
+func TestLookup(t *testing.T) {
+	if d, ok := Lookup("TEST_DEFINITION_NOT_FOUND"); !ok || d != errTestDefinitionNotFound {
+		t.Errorf("Lookup() = %v, %v, want %v, true", d, ok, errTestDefinitionNotFound)
+	}
+	if _, ok := Lookup("TEST_UNKNOWN_CODE"); ok {
+		t.Error("Lookup() should not find unknown codes")
+	}
+}
+
+func TestDefinition_New(t *testing.T) {
+	tests := []struct {
+		name string
+		args []any
+		want string
+	}{
+		{"Placeholder filled", []any{42}, "user 42 not found"},
+		{"Placeholder missing", nil, "user {id} not found"},
+		{"Extra arguments", []any{42, "on tenant", 7}, "user 42 not found on tenant 7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detail := Details(errTestDefinitionNotFound.New(tt.args...))
+			if detail.Message() != tt.want {
+				t.Errorf("Definition.New() message = %v, want %v", detail.Message(), tt.want)
+			}
+			if detail.Code() != "TEST_DEFINITION_NOT_FOUND" || detail.Kind() != NotFound {
+				t.Errorf("Definition.New() code = %v, kind = %v", detail.Code(), detail.Kind())
+			}
+			if detail.Func() != "func1" || !strings.HasSuffix(detail.File(), "definition_test.go") {
+				t.Errorf("Definition.New() caller = %v %v", detail.File(), detail.Func())
+			}
+		})
+	}
+}
+
+func TestDefinition_Is(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"Error is nil", nil, false},
+		{"Error from definition", errTestDefinitionNotFound.New(1), true},
+		{"Error with same message", New("user 1 not found"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errTestDefinitionNotFound.Is(tt.err); got != tt.want {
+				t.Errorf("Definition.Is() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogJSON(t *testing.T) {
+	bs, err := CatalogJSON()
+	if err != nil {
+		t.Fatalf("CatalogJSON() error = %v", err)
+	}
+
+	var entries []CatalogEntry
+	if err = json.Unmarshal(bs, &entries); err != nil {
+		t.Fatalf("CatalogJSON() returned invalid JSON: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.Code == "TEST_DEFINITION_NOT_FOUND" {
+			if entry.Kind != NotFound || entry.DocsURL == "" || entry.Template != "user {id} not found" {
+				t.Errorf("CatalogJSON() entry = %+v", entry)
+			}
+			return
+		}
+	}
+	t.Error("CatalogJSON() should contain TEST_DEFINITION_NOT_FOUND")
+}
+
+func TestDefinition_HTTPStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		def  *Definition
+		want int
+	}{
+		{"Status from kind", errTestDefinitionNotFound, 404},
+		{"Status from option", Define("TEST_DEFINITION_STATUS", FailedPrecondition, "invalid", HTTPStatus(422)), 422},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.def.HTTPStatus(); got != tt.want {
+				t.Errorf("Definition.HTTPStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
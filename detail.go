@@ -2,8 +2,13 @@ package errors
 
 import (
 	"fmt"
-	"runtime/debug"
+	"io"
+	"log/slog"
+	"maps"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Detail struct {
@@ -12,6 +17,22 @@ type Detail struct {
 	funcName string
 	message  string
 	stack    string
+	code     string
+	kind     Kind
+	fields   Fields
+	hints    []string
+	docsURL  string
+	cause    error
+
+	fingerprint   string
+	contextFields Fields
+	ctxErr        error
+	pcs           []uintptr
+	metadata      *Metadata
+	labels        map[string]string
+	time          time.Time
+	ops           []OpStep
+	trace         []Frame
 }
 
 // New constructs a new error instance with detailed information.
@@ -32,15 +53,7 @@ type Detail struct {
 //	fmt.Println(err) // Outputs error detail with message "File not found in directory /home/user"
 func New(args ...any) error {
 	msg := buildMessage(args...)
-	file, line, funcName := callerInfos(2)
-	stack := debug.Stack()
-	return &Detail{
-		file:     file,
-		line:     line,
-		funcName: funcName,
-		message:  msg,
-		stack:    string(stack),
-	}
+	return newDetail(1, msg)
 }
 
 // Newf constructs a new error instance with detailed information. It accepts a format string and variadic arguments
@@ -66,15 +79,7 @@ func New(args ...any) error {
 // to construct the error message.
 func Newf(format string, args ...any) error {
 	msg := buildMessageByFormat(format, args...)
-	file, line, funcName := callerInfos(2)
-	stack := debug.Stack()
-	return &Detail{
-		file:     file,
-		line:     line,
-		funcName: funcName,
-		message:  msg,
-		stack:    string(stack),
-	}
+	return newDetail(1, msg)
 }
 
 // NewSkipCaller constructs a new Detail structure. It takes a variable amount of parameters
@@ -106,15 +111,7 @@ func Newf(format string, args ...any) error {
 //	fmt.Println(secondError)
 func NewSkipCaller(skipCaller int, args ...any) error {
 	msg := buildMessage(args...)
-	file, line, funcName := callerInfos(skipCaller + 1)
-	stack := debug.Stack()
-	return &Detail{
-		file:     file,
-		line:     line,
-		funcName: funcName,
-		message:  msg,
-		stack:    string(stack),
-	}
+	return newDetail(skipCaller, msg)
 }
 
 // NewSkipCallerf creates a new error structured type `ErrorDetail`. It includes detailed logging information such as
@@ -148,15 +145,54 @@ func NewSkipCaller(skipCaller int, args ...any) error {
 //	fmt.Println(err)
 func NewSkipCallerf(skipCaller int, format string, args ...any) error {
 	msg := buildMessageByFormat(format, args...)
-	file, line, funcName := callerInfos(skipCaller + 1)
-	stack := debug.Stack()
-	return &Detail{
-		file:     file,
-		line:     line,
-		funcName: funcName,
-		message:  msg,
-		stack:    string(stack),
+	return newDetail(skipCaller, msg)
+}
+
+// WithHint attaches a remediation hint to the error, telling what can be done to solve it. Hints are
+// surfaced by the "%+v" verb, by the slog output and by the renderers of the package. When err is a
+// *Detail, a copy of it with the additional hint is returned; any other error is wrapped by a *Detail
+// that takes the caller of WithHint as its origin and keeps the code, fields and hints of a *Detail
+// found in the chain of err.
+//
+// Parameters:
+//   - err: The error that receives the hint.
+//   - hint: The remediation hint.
+//
+// Returns:
+//   - error: A *Detail with the hint, or nil when err is nil.
+//
+// Example:
+//
+//	err := WithHint(New("missing API key"), "check that the API_KEY environment variable is set")
+//	fmt.Println(Details(err).Hints()) // [check that the API_KEY environment variable is set]
+func WithHint(err error, hint string) error {
+	if err == nil {
+		return nil
+	}
+	detail := detailOf(err, 1)
+	detail.hints = append(slices.Clip(detail.hints), hint)
+	return detail
+}
+
+// WithDocsURL attaches a documentation URL to the error, overriding the one inherited from its
+// Definition. The URL is surfaced by the "%+v" verb, by the slog output and as the type of the problem
+// details returned by ToProblem. When err is a *Detail, a copy of it is returned; any other error is
+// wrapped by a *Detail that takes the caller of WithDocsURL as its origin and keeps the code, fields and
+// hints of a *Detail found in the chain of err.
+//
+// Parameters:
+//   - err: The error that receives the documentation URL.
+//   - url: The documentation URL.
+//
+// Returns:
+//   - error: A *Detail with the documentation URL, or nil when err is nil.
+func WithDocsURL(err error, url string) error {
+	if err == nil {
+		return nil
 	}
+	detail := detailOf(err, 1)
+	detail.docsURL = url
+	return detail
 }
 
 // Error constructs a detailed error string containing the cause of the error and the
@@ -173,9 +209,92 @@ func NewSkipCallerf(skipCaller int, format string, args ...any) error {
 //
 // Returns:
 //   - string: A detailed string representation of the error, formatted as
-//     "[CAUSE]: <cause of the error> [STACK]: <debug stack>"
+//     "[CAUSE]: <cause of the error> [STACK]: <debug stack>", unless another format is set by
+//     ConfigFormat.
 func (e *Detail) Error() string {
-	return fmt.Sprint("[CAUSE]: ", e.Cause(), " [STACK]: ", e.stack)
+	switch loadConfig().Format {
+	case ErrorFormatCause:
+		return e.Cause()
+	case ErrorFormatMessage:
+		return e.Message()
+	default:
+		return fmt.Sprint("[CAUSE]: ", e.Cause(), " [STACK]: ", e.stack)
+	}
+}
+
+// Format implements fmt.Formatter. The "%s" and "%v" verbs print the same string returned by Error,
+// "%q" prints it quoted and "%+v" prints a multi-line report with the cause, the hints, the
+// documentation URL and the debug stack of the error.
+func (e *Detail) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		_, _ = io.WriteString(s, e.Cause())
+		for _, hint := range e.hints {
+			_, _ = io.WriteString(s, "\nhint: "+hint)
+		}
+		if e.docsURL != "" {
+			_, _ = io.WriteString(s, "\ndocs: "+e.docsURL)
+		}
+		_, _ = io.WriteString(s, "\n"+strings.TrimRight(e.stack, "\n"))
+	case verb == 'q':
+		_, _ = io.WriteString(s, strconv.Quote(e.Error()))
+	default:
+		_, _ = io.WriteString(s, e.Error())
+	}
+}
+
+// LogValue implements slog.LogValuer, so a *Detail logged with log/slog is written as a group with the
+// message, the origin and the remaining attributes of the error, instead of the single line returned by
+// Error.
+func (e *Detail) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("message", e.Message()),
+		slog.String("file", e.file),
+		slog.Int("line", e.Line()),
+		slog.String("func", e.funcName),
+	}
+	if !e.time.IsZero() {
+		attrs = append(attrs, slog.Time("time", e.time))
+	}
+	if e.code != "" {
+		attrs = append(attrs, slog.String("code", e.code), slog.String("kind", e.Kind().String()))
+	}
+	if fields := e.Fields(); len(fields) > 0 {
+		fieldAttrs := make([]any, 0, len(fields))
+		for _, k := range sortedKeys(fields) {
+			fieldAttrs = append(fieldAttrs, slog.Any(k, fields[k]))
+		}
+		attrs = append(attrs, slog.Group("fields", fieldAttrs...))
+	}
+	if len(e.hints) > 0 {
+		attrs = append(attrs, slog.Any("hints", e.hints))
+	}
+	if e.docsURL != "" {
+		attrs = append(attrs, slog.String("docs_url", e.docsURL))
+	}
+	if trail := OpTrail(e); trail != "" {
+		attrs = append(attrs, slog.String("op_trail", trail))
+	}
+	if trace := ReturnTrace(e); len(trace) > 1 {
+		locations := make([]string, 0, len(trace))
+		for _, frame := range trace {
+			locations = append(locations, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+		}
+		attrs = append(attrs, slog.Any("return_trace", locations))
+	}
+	if len(e.labels) > 0 {
+		labelAttrs := make([]any, 0, len(e.labels))
+		for _, k := range sortedKeys(e.labels) {
+			labelAttrs = append(labelAttrs, slog.String(k, e.labels[k]))
+		}
+		attrs = append(attrs, slog.Group("goroutine_labels", labelAttrs...))
+	}
+	if e.metadata != nil {
+		if metadataAttrs := e.metadata.logAttrs(); len(metadataAttrs) > 0 {
+			attrs = append(attrs, slog.Group("metadata", metadataAttrs...))
+		}
+	}
+	return slog.GroupValue(attrs...)
 }
 
 // PrintStackTrace prints the debug stack of the Detail instance.
@@ -204,17 +323,37 @@ func (e *Detail) PrintCause() {
 // Returns:
 //   - string: A string representation of the cause of the error.
 func (e *Detail) Cause() string {
-	return fmt.Sprint("(", e.file, ":", e.line, ")", " ", e.funcName, ": ", e.message)
+	return fmt.Sprint("(", e.file, ":", e.line, ")", " ", e.funcName, ": ", e.Message())
 }
 
 // Message returns the message associated with the Detail instance.
 // This method can be used to extract only the error message from an Detail object.
+// When the Detail wraps another error, the message of the wrapped error is appended to it,
+// separated by ": ".
 //
 // No parameters.
 //
 // Returns:
 //   - string: The error message.
 func (e *Detail) Message() string {
+	if e.cause == nil {
+		return e.message
+	}
+	causeMessage := MessageOf(e.cause)
+	if e.message == "" {
+		return causeMessage
+	} else if causeMessage == "" {
+		return e.message
+	}
+	return e.message + ": " + causeMessage
+}
+
+// OwnMessage returns the message given to the Detail when it was created, without the messages of the
+// errors it wraps. It is empty for a Detail created by Wrap without a message.
+//
+// Returns:
+//   - string: The own message of the Detail.
+func (e *Detail) OwnMessage() string {
 	return e.message
 }
 
@@ -252,6 +391,88 @@ func (e *Detail) Func() string {
 	return e.funcName
 }
 
+// Code returns the code of the Definition used to create the error, or an empty string when
+// the error was not created from a Definition.
+//
+// Returns:
+//   - string: The error code.
+func (e *Detail) Code() string {
+	return e.code
+}
+
+// Kind returns the Kind of the error. Errors that were not created from a Definition are
+// reported as Unknown.
+//
+// Returns:
+//   - Kind: The kind of the error.
+func (e *Detail) Kind() Kind {
+	if e.kind == "" {
+		return Unknown
+	}
+	return e.kind
+}
+
+// Fields returns a copy of the structured fields recorded on the error, such as the values of the
+// template placeholders of a Definition and the fields extracted from the context by the context-aware
+// constructors, with the values selected by the redaction policy set by ConfigRedact and ConfigRedactor
+// replaced. The template placeholders take precedence over the context fields. It returns nil when the
+// error has no fields.
+//
+// Returns:
+//   - Fields: The fields of the error.
+func (e *Detail) Fields() Fields {
+	if len(e.fields) == 0 && len(e.contextFields) == 0 {
+		return nil
+	}
+	fields := redactFields(e.contextFields)
+	if fields == nil {
+		return redactFields(e.fields)
+	}
+	maps.Copy(fields, redactFields(e.fields))
+	return fields
+}
+
+// ContextFields returns a copy of the fields extracted from the context by the context-aware
+// constructors, such as a request ID, which are kept apart from the template placeholders so they are
+// not exposed by ToProblem. It returns nil when the error has no context fields.
+//
+// Returns:
+//   - Fields: The context fields of the error.
+func (e *Detail) ContextFields() Fields {
+	return redactFields(e.contextFields)
+}
+
+// redactFields returns a copy of fields with the values selected by the redaction policy replaced, or nil
+// when fields is empty.
+func redactFields(fields Fields) Fields {
+	if len(fields) == 0 {
+		return nil
+	}
+	c := loadConfig()
+	redacted := make(Fields, len(fields))
+	for k, v := range fields {
+		redacted[k] = c.redact(k, v)
+	}
+	return redacted
+}
+
+// Hints returns the remediation hints attached to the error, either by its Definition or by WithHint.
+//
+// Returns:
+//   - []string: The hints of the error.
+func (e *Detail) Hints() []string {
+	return slices.Clone(e.hints)
+}
+
+// DocsURL returns the documentation URL of the error, either inherited from its Definition or attached
+// by WithDocsURL.
+//
+// Returns:
+//   - string: The documentation URL of the error.
+func (e *Detail) DocsURL() string {
+	return e.docsURL
+}
+
 // Stack returns the debug stack associated with the Detail instance.
 // This method can be used to retrieve the stack trace of the error for debugging
 // or logging purposes.
@@ -261,3 +482,32 @@ func (e *Detail) Func() string {
 func (e *Detail) Stack() string {
 	return e.stack
 }
+
+// newDetail builds a Detail for the given message, capturing the caller information and the debug
+// stack, as configured by Configure. The skip value follows the NewSkipCaller convention, where 1 identifies the caller of the
+// exported constructor that invoked newDetail.
+func newDetail(skip int, msg string) *Detail {
+	file, line, funcName := callerInfos(skip + 2)
+	stack, pcs := captureStack(skip + 2)
+	return &Detail{
+		file:     file,
+		line:     line,
+		funcName: funcName,
+		message:  msg,
+		stack:    stack,
+		pcs:      pcs,
+		metadata: captureMetadata(stack),
+		time:     time.Now(),
+	}
+}
+
+// detailOf returns a copy of err when it is a *Detail, so it can be changed without affecting the
+// original error, or a new *Detail wrapping err, which inherits the classification of a *Detail found in
+// its chain, such as one wrapped by fmt.Errorf. The skip value follows the newDetail convention.
+func detailOf(err error, skip int) *Detail {
+	if detail, ok := err.(*Detail); ok {
+		clone := *detail
+		return &clone
+	}
+	return wrap(skip+1, err, "")
+}
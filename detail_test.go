@@ -1,6 +1,12 @@
 package errors
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -91,3 +97,95 @@ func TestNewSkipCallerf(t *testing.T) {
 		t.Error("NewSkipCallerf() should not return nil")
 	}
 }
+
+func TestWithHint(t *testing.T) {
+	original := New("missing API key")
+	err := WithHint(original, "check that the API_KEY environment variable is set")
+
+	if hints := Details(err).Hints(); len(hints) != 1 || hints[0] != "check that the API_KEY environment variable is set" {
+		t.Errorf("Detail.Hints() = %v", hints)
+	}
+	if hints := Details(original).Hints(); len(hints) != 0 {
+		t.Errorf("WithHint() should not change the original error, got hints %v", hints)
+	}
+	if WithHint(nil, "hint") != nil {
+		t.Error("WithHint() should return nil for nil errors")
+	}
+}
+
+func TestWithHintWrappedDetail(t *testing.T) {
+	original := fmt.Errorf("loading profile: %w", errTestWrapNotFound.New(42))
+	err := WithHint(original, "retry later")
+
+	detail := Details(err)
+	if detail.Code() != "TEST_WRAP_NOT_FOUND" || detail.Fields()["id"] != 42 {
+		t.Errorf("WithHint() code = %v, fields = %v", detail.Code(), detail.Fields())
+	}
+	if hints := detail.Hints(); len(hints) != 2 || hints[0] != "check the id" || hints[1] != "retry later" {
+		t.Errorf("Detail.Hints() = %v", hints)
+	}
+	if !errors.Is(err, original) || !errTestWrapNotFound.Is(err) {
+		t.Errorf("WithHint() lost the chain of %v", original)
+	}
+	if url := Details(WithDocsURL(original, "https://docs.example.com")).DocsURL(); url != "https://docs.example.com" {
+		t.Errorf("WithDocsURL() DocsURL = %v", url)
+	}
+}
+
+func TestWithDocsURL(t *testing.T) {
+	err := WithDocsURL(errors.New("plain error"), "https://docs.example.com/errors/plain")
+
+	detail := Details(err)
+	if detail.DocsURL() != "https://docs.example.com/errors/plain" {
+		t.Errorf("Detail.DocsURL() = %v", detail.DocsURL())
+	}
+	if detail.Message() != "plain error" || detail.Func() != "TestWithDocsURL" {
+		t.Errorf("WithDocsURL() message = %v, func = %v", detail.Message(), detail.Func())
+	}
+}
+
+func TestDetail_Format(t *testing.T) {
+	e := &Detail{file: "file.go", line: "10", funcName: "function", message: "message", stack: "stack trace\n",
+		hints: []string{"try again"}, docsURL: "https://docs.example.com"}
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{"Verb v", "%v", e.Error()},
+		{"Verb s", "%s", e.Error()},
+		{"Verb q", "%q", strconv.Quote(e.Error())},
+		{"Verb +v", "%+v", "(file.go:10) function: message\nhint: try again\ndocs: https://docs.example.com\nstack trace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprintf(tt.format, e); got != tt.want {
+				t.Errorf("Detail.Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetail_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	err := WithHint(New("failed"), "try again")
+	logger.Error("request failed", "error", err)
+
+	var entry struct {
+		Error struct {
+			Message string   `json:"message"`
+			Func    string   `json:"func"`
+			Hints   []string `json:"hints"`
+		} `json:"error"`
+	}
+	if jsonErr := json.Unmarshal(buf.Bytes(), &entry); jsonErr != nil {
+		t.Fatalf("invalid log output %s: %v", buf.String(), jsonErr)
+	}
+	if entry.Error.Message != "failed" || entry.Error.Func != "TestDetail_LogValue" || len(entry.Error.Hints) != 1 {
+		t.Errorf("Detail.LogValue() = %s", buf.String())
+	}
+}
@@ -0,0 +1,117 @@
+// Package ecserrors maps errors of the github.com/tech4works/errors package to the fields of the Elastic
+// Common Schema (ECS), so they can be indexed by Elasticsearch without per-service mappings.
+package ecserrors
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/tech4works/errors"
+)
+
+// Fields returns the ECS fields of err as nested maps, ready to be merged into a JSON log document:
+//
+//	{
+//	  "error": {"message": ..., "type": ..., "code": ..., "stack_trace": ...},
+//	  "log": {"origin": {"file": {"name": ..., "line": ...}, "function": ...}},
+//	  "labels": {...}
+//	}
+//
+// The error.type field is the code of the error or, when it has none, the Go type of the innermost
+// error of its chain. The fields of the error are reported as ECS labels, formatted as strings.
+//
+// Parameters:
+//   - err: The error to be mapped.
+//
+// Returns:
+//   - map[string]any: The ECS fields, or nil when err is nil.
+func Fields(err error) map[string]any {
+	if err == nil {
+		return nil
+	}
+
+	detail := errors.Details(err)
+	errorFields := map[string]any{
+		"message":     detail.Message(),
+		"type":        errorType(err, detail),
+		"stack_trace": detail.Stack(),
+	}
+	if detail.Code() != "" {
+		errorFields["code"] = detail.Code()
+	}
+
+	fields := map[string]any{
+		"error": errorFields,
+		"log": map[string]any{
+			"origin": map[string]any{
+				"file": map[string]any{
+					"name": detail.File(),
+					"line": detail.Line(),
+				},
+				"function": detail.Func(),
+			},
+		},
+	}
+	if labels := labels(detail.Fields()); len(labels) > 0 {
+		fields["labels"] = labels
+	}
+	return fields
+}
+
+// Attr returns the ECS fields of err as a slog attribute. The attribute has an empty key, so handlers
+// inline its "error", "log" and "labels" groups at the top level of the record, as ECS expects.
+//
+// Parameters:
+//   - err: The error to be mapped.
+//
+// Returns:
+//   - slog.Attr: The ECS attribute group, empty when err is nil.
+//
+// Example:
+//
+//	logger.Error("payment failed", ecserrors.Attr(err))
+//	// {"msg":"payment failed","error":{"message":...},"log":{"origin":{...}}}
+func Attr(err error) slog.Attr {
+	return slog.Attr{Key: "", Value: slog.GroupValue(attrs(Fields(err))...)}
+}
+
+func attrs(fields map[string]any) []slog.Attr {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make([]slog.Attr, 0, len(fields))
+	for _, k := range keys {
+		if nested, ok := fields[k].(map[string]any); ok {
+			result = append(result, slog.Attr{Key: k, Value: slog.GroupValue(attrs(nested)...)})
+		} else {
+			result = append(result, slog.Any(k, fields[k]))
+		}
+	}
+	return result
+}
+
+func labels(fields errors.Fields) map[string]any {
+	if len(fields) == 0 {
+		return nil
+	}
+	result := make(map[string]any, len(fields))
+	for k, v := range fields {
+		result[k] = fmt.Sprint(v)
+	}
+	return result
+}
+
+func errorType(err error, detail *errors.Detail) string {
+	if detail.Code() != "" {
+		return detail.Code()
+	}
+	errors.Walk(err, func(next error, _ int) bool {
+		err = next
+		return false
+	})
+	return fmt.Sprintf("%T", err)
+}
@@ -0,0 +1,75 @@
+package ecserrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/tech4works/errors"
+)
+
+var errVolumeFull = errors.Define("ECS_VOLUME_FULL", errors.ResourceExhausted, "volume {volume} has {free} bytes free")
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantType string
+		wantCode any
+	}{
+		{"Error from definition", errVolumeFull.New("data", 512), "ECS_VOLUME_FULL", "ECS_VOLUME_FULL"},
+		{"Wrapped standard error", errors.Wrap(io.EOF, "reading body"), "*errors.errorString", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := Fields(tt.err)
+			errorFields := fields["error"].(map[string]any)
+			if errorFields["type"] != tt.wantType || errorFields["code"] != tt.wantCode {
+				t.Errorf("Fields() error = %v", errorFields)
+			}
+			if errorFields["stack_trace"] == "" || errorFields["message"] != errors.Details(tt.err).Message() {
+				t.Errorf("Fields() error = %v", errorFields)
+			}
+
+			origin := fields["log"].(map[string]any)["origin"].(map[string]any)
+			if origin["function"] != "TestFields" || origin["file"].(map[string]any)["name"] != "ecserrors/ecserrors_test.go" {
+				t.Errorf("Fields() log.origin = %v", origin)
+			}
+		})
+	}
+
+	if Fields(nil) != nil {
+		t.Error("Fields() should return nil for nil errors")
+	}
+}
+
+func TestAttr(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error("lookup failed", Attr(errVolumeFull.New("data", 512)))
+
+	var doc struct {
+		Error struct {
+			Message string `json:"message"`
+			Code    string `json:"code"`
+		} `json:"error"`
+		Log struct {
+			Origin struct {
+				File struct {
+					Line int `json:"line"`
+				} `json:"file"`
+			} `json:"origin"`
+		} `json:"log"`
+		Labels map[string]string `json:"labels"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid log output %s: %v", buf.String(), err)
+	}
+	if doc.Error.Message != "volume data has 512 bytes free" || doc.Error.Code != "ECS_VOLUME_FULL" ||
+		doc.Log.Origin.File.Line == 0 || doc.Labels["volume"] != "data" || doc.Labels["free"] != "512" {
+		t.Errorf("Attr() output = %s", buf.String())
+	}
+}
@@ -0,0 +1,243 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WriteOpenAPI writes an OpenAPI 3 document with the components describing the given catalog entries.
+// Every code gets a problem details schema, named after the code in PascalCase with a "Problem" suffix,
+// and a response with the "application/problem+json" content, named after the code, ready to be
+// referenced by the API operations.
+//
+// Parameters:
+//   - w: The writer where the JSON document is written.
+//   - entries: The catalog entries, usually the result of Catalog.
+//
+// Returns:
+//   - error: An error if the document could not be encoded or written.
+//
+// Example:
+//
+//	var ErrUserNotFound = Define("USER_NOT_FOUND", NotFound, "user {id} not found")
+//
+//	_ = WriteOpenAPI(os.Stdout, Catalog())
+//	// The operations can then reference "#/components/responses/USER_NOT_FOUND".
+func WriteOpenAPI(w io.Writer, entries []CatalogEntry) error {
+	codes := make([]string, 0, len(entries))
+	schemas := map[string]any{
+		"Problem": map[string]any{
+			"type":     "object",
+			"required": []string{"type", "title", "status"},
+			"properties": map[string]any{
+				"type":     map[string]any{"type": "string", "format": "uri-reference"},
+				"title":    map[string]any{"type": "string"},
+				"status":   map[string]any{"type": "integer"},
+				"detail":   map[string]any{"type": "string"},
+				"instance": map[string]any{"type": "string", "format": "uri-reference"},
+				"code":     map[string]any{"$ref": "#/components/schemas/ErrorCode"},
+				"params":   map[string]any{"type": "object", "additionalProperties": true},
+			},
+		},
+	}
+	responses := map[string]any{}
+
+	for _, entry := range entries {
+		codes = append(codes, entry.Code)
+
+		params := map[string]any{}
+		for _, name := range entry.Params {
+			params[name] = openAPIType(entry.ParamTypes[name])
+		}
+		schema := map[string]any{
+			"description": entry.Template,
+			"allOf": []any{
+				map[string]any{"$ref": "#/components/schemas/Problem"},
+				map[string]any{
+					"type":     "object",
+					"required": []string{"code"},
+					"properties": map[string]any{
+						"code":   map[string]any{"type": "string", "enum": []string{entry.Code}},
+						"status": map[string]any{"type": "integer", "enum": []int{entry.HTTPStatus}},
+						"params": map[string]any{
+							"type":       "object",
+							"properties": params,
+						},
+					},
+				},
+			},
+		}
+		if entry.DocsURL != "" {
+			schema["externalDocs"] = map[string]any{"url": entry.DocsURL}
+		}
+
+		name := entry.Name() + "Problem"
+		schemas[name] = schema
+
+		description := entry.PublicMessage
+		if description == "" {
+			description = entry.Template
+		}
+		responses[entry.Code] = map[string]any{
+			"description": description,
+			"content": map[string]any{
+				"application/problem+json": map[string]any{
+					"schema": map[string]any{"$ref": "#/components/schemas/" + name},
+				},
+			},
+		}
+	}
+	schemas["ErrorCode"] = map[string]any{"type": "string", "enum": codes}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]any{
+		"openapi": "3.0.3",
+		"info":    map[string]any{"title": "Error catalog", "version": "1.0.0"},
+		"paths":   map[string]any{},
+		"components": map[string]any{
+			"schemas":   schemas,
+			"responses": responses,
+		},
+	})
+}
+
+// WriteTypeScript writes a TypeScript module describing the given catalog entries. The module exports
+// the ErrorCode union, a problem type per code with its typed params, the ApiProblem discriminated union
+// over the code property and the errorCatalog constant with the attributes of every code.
+//
+// Parameters:
+//   - w: The writer where the TypeScript module is written.
+//   - entries: The catalog entries, usually the result of Catalog.
+//
+// Returns:
+//   - error: An error if the module could not be written.
+func WriteTypeScript(w io.Writer, entries []CatalogEntry) error {
+	var sb strings.Builder
+	sb.WriteString("// Code generated from the error catalog. DO NOT EDIT.\n\n")
+
+	sb.WriteString("export type ErrorCode =")
+	if len(entries) == 0 {
+		sb.WriteString(" never")
+	}
+	for _, entry := range entries {
+		sb.WriteString("\n  | " + jsString(entry.Code))
+	}
+	sb.WriteString(";\n\n")
+
+	sb.WriteString(`export interface Problem<C extends ErrorCode, P> {
+  type: string;
+  title: string;
+  status: number;
+  detail?: string;
+  instance?: string;
+  code: C;
+  params?: P;
+}
+`)
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name() + "Problem"
+		names = append(names, name)
+
+		params := "Record<string, never>"
+		if len(entry.Params) > 0 {
+			var fields []string
+			for _, param := range entry.Params {
+				fields = append(fields, fmt.Sprintf("%s: %s", jsString(param), typeScriptType(entry.ParamTypes[param])))
+			}
+			params = "{ " + strings.Join(fields, "; ") + " }"
+		}
+
+		sb.WriteString("\n")
+		if entry.Template != "" {
+			sb.WriteString("/** " + strings.ReplaceAll(entry.Template, "*/", "*\\/") + " */\n")
+		}
+		sb.WriteString(fmt.Sprintf("export type %s = Problem<%s, %s>;\n", name, jsString(entry.Code), params))
+	}
+
+	sb.WriteString("\nexport type ApiProblem =")
+	if len(names) == 0 {
+		sb.WriteString(" never")
+	}
+	for _, name := range names {
+		sb.WriteString("\n  | " + name)
+	}
+	sb.WriteString(";\n\n")
+
+	sb.WriteString("export const errorCatalog = {\n")
+	for _, entry := range entries {
+		sb.WriteString(fmt.Sprintf("  %s: { kind: %s, status: %d, template: %s, docsUrl: %s },\n",
+			jsString(entry.Code), jsString(string(entry.Kind)), entry.HTTPStatus, jsString(entry.Template),
+			jsString(entry.DocsURL)))
+	}
+	sb.WriteString("} as const;\n\n")
+
+	sb.WriteString(`export function isProblem<C extends ErrorCode>(
+  problem: ApiProblem,
+  code: C,
+): problem is Extract<ApiProblem, { code: C }> {
+  return problem.code === code;
+}
+`)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func openAPIType(goType string) map[string]any {
+	switch goType {
+	case "string":
+		return map[string]any{"type": "string"}
+	case "bool":
+		return map[string]any{"type": "boolean"}
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return map[string]any{"type": "integer"}
+	case "float32", "float64":
+		return map[string]any{"type": "number"}
+	default:
+		return map[string]any{}
+	}
+}
+
+func typeScriptType(goType string) string {
+	switch openAPIType(goType)["type"] {
+	case "string":
+		return "string"
+	case "boolean":
+		return "boolean"
+	case "integer", "number":
+		return "number"
+	default:
+		return "unknown"
+	}
+}
+
+func jsString(s string) string {
+	bs, _ := json.Marshal(s)
+	return string(bs)
+}
+
+// Name returns the code of the entry in Pascal case, e.g. "UserNotFound" for "USER_NOT_FOUND", which
+// is used to name the types and identifiers generated for the entry. Names that would start with a digit
+// are prefixed with "Code", e.g. "Code404Page" for "404_PAGE", so they are valid identifiers.
+func (e CatalogEntry) Name() string {
+	var sb strings.Builder
+	for _, word := range strings.FieldsFunc(e.Code, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		first, size := utf8.DecodeRuneInString(word)
+		sb.WriteRune(unicode.ToUpper(first))
+		sb.WriteString(strings.ToLower(word[size:]))
+	}
+	name := sb.String()
+	if first, _ := utf8.DecodeRuneInString(name); unicode.IsDigit(first) {
+		name = "Code" + name
+	}
+	return name
+}
@@ -0,0 +1,102 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var testCatalogEntries = []CatalogEntry{
+	{
+		Code:       "USER_NOT_FOUND",
+		Kind:       NotFound,
+		Template:   "user {id} not found",
+		Params:     []string{"id"},
+		ParamTypes: map[string]string{"id": "int64"},
+		DocsURL:    "https://docs.example.com/errors/USER_NOT_FOUND",
+		HTTPStatus: 404,
+	},
+	{Code: "SERVICE_UNAVAILABLE", Kind: Unavailable, Template: "service unavailable", HTTPStatus: 503},
+}
+
+func TestWriteOpenAPI(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteOpenAPI(&buf, testCatalogEntries); err != nil {
+		t.Fatalf("WriteOpenAPI() error = %v", err)
+	}
+
+	var doc struct {
+		OpenAPI    string `json:"openapi"`
+		Components struct {
+			Schemas   map[string]json.RawMessage `json:"schemas"`
+			Responses map[string]json.RawMessage `json:"responses"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("WriteOpenAPI() returned invalid JSON: %v", err)
+	}
+	for _, name := range []string{"Problem", "ErrorCode", "UserNotFoundProblem", "ServiceUnavailableProblem"} {
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("WriteOpenAPI() should contain the %s schema", name)
+		}
+	}
+	if _, ok := doc.Components.Responses["USER_NOT_FOUND"]; !ok {
+		t.Error("WriteOpenAPI() should contain the USER_NOT_FOUND response")
+	}
+	if !strings.Contains(string(doc.Components.Schemas["UserNotFoundProblem"]), `"integer"`) {
+		t.Error("WriteOpenAPI() should type the id param as integer")
+	}
+}
+
+func TestWriteTypeScript(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTypeScript(&buf, testCatalogEntries); err != nil {
+		t.Fatalf("WriteTypeScript() error = %v", err)
+	}
+
+	got := buf.String()
+	for _, want := range []string{
+		`| "USER_NOT_FOUND"`,
+		`export type UserNotFoundProblem = Problem<"USER_NOT_FOUND", { "id": number }>;`,
+		`export type ServiceUnavailableProblem = Problem<"SERVICE_UNAVAILABLE", Record<string, never>>;`,
+		"| UserNotFoundProblem",
+		"  params?: P;",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WriteTypeScript() should contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestWriteTypeScriptEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTypeScript(&buf, nil); err != nil {
+		t.Fatalf("WriteTypeScript() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "export type ErrorCode = never;") {
+		t.Errorf("WriteTypeScript() = %s", buf.String())
+	}
+}
+
+func TestCatalogEntry_Name(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"USER_NOT_FOUND", "UserNotFound"},
+		{"payment.declined-2", "PaymentDeclined2"},
+		{"A", "A"},
+		{"404_PAGE", "Code404Page"},
+		{"ÉCHEC_PAIEMENT", "ÉchecPaiement"},
+		{"über", "Über"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := (CatalogEntry{Code: tt.code}).Name(); got != tt.want {
+				t.Errorf("CatalogEntry.Name() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"regexp"
+	"strconv"
+)
+
+// FingerprintOption customizes how Fingerprint computes the fingerprint of an error.
+type FingerprintOption func(c *fingerprintConfig)
+
+type fingerprintConfig struct {
+	ignoreLines bool
+}
+
+var (
+	uuidRegex   = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	hexRegex    = regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b|\b[0-9a-f]{16,}\b`)
+	numberRegex = regexp.MustCompile(`\d+(\.\d+)?`)
+	quotedRegex = regexp.MustCompile(`"[^"]*"|'[^']*'`)
+)
+
+// IgnoreLineNumbers makes Fingerprint ignore the line numbers of the frames, so the fingerprint does not
+// change when unrelated code is added above the failing line between deployments.
+func IgnoreLineNumbers() FingerprintOption {
+	return func(c *fingerprintConfig) {
+		c.ignoreLines = true
+	}
+}
+
+// Fingerprint returns a stable hash that identifies the error for grouping and deduplication. The
+// same bug produces the same fingerprint across requests and deployments, because the hash is made of:
+//   - the code and the template of the Definition of the error, instead of its message, when it has one;
+//   - otherwise the message of the error with numbers, hexadecimal values, UUIDs and quoted strings
+//     replaced by placeholders;
+//   - the function, file name and, unless IgnoreLineNumbers is given, line of the frames where the error
+//     was created, ignoring the runtime frames, goroutine IDs, argument values and file directories.
+//
+// Errors without a *Detail in their chain carry no frames, so their hash is made only of their type and
+// their normalized message.
+//
+// A fingerprint set with WithFingerprint on the error, or on any error of its chain, takes precedence.
+//
+// Parameters:
+//   - err: The error to be fingerprinted.
+//   - opts: Optional FingerprintOption values.
+//
+// Returns:
+//   - string: The hexadecimal fingerprint, or an empty string when err is nil.
+//
+// Example:
+//
+//	fp1 := Fingerprint(Newf("user %d not found", 1))
+//	fp2 := Fingerprint(Newf("user %d not found", 2))
+//	fmt.Println(fp1 == fp2) // true, when created by the same line
+func Fingerprint(err error, opts ...FingerprintOption) string {
+	if err == nil {
+		return ""
+	}
+
+	var override string
+	walk(err, func(err error) bool {
+		detail, ok := err.(*Detail)
+		if ok && detail.fingerprint != "" {
+			override = detail.fingerprint
+		}
+		return override != ""
+	})
+	if override != "" {
+		return override
+	}
+
+	var config fingerprintConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	hash := sha256.New()
+	write := func(s string) {
+		hash.Write([]byte(s))
+		hash.Write([]byte{0})
+	}
+
+	if !walk(err, func(err error) bool {
+		_, ok := err.(*Detail)
+		return ok
+	}) {
+		// Without a *Detail in the chain there is no origin, and the frames of Details would be the ones of
+		// the caller of Fingerprint.
+		write(typeOf(err))
+		write(NormalizeMessage(MessageOf(err)))
+		return hex.EncodeToString(hash.Sum(nil)[:16])
+	}
+
+	detail := Details(err)
+	origin := Origin(err)
+	if def, ok := lookup(detail.code); ok {
+		write(def.code)
+		write(def.template)
+	} else {
+		write(detail.code)
+		write(NormalizeMessage(origin.Message()))
+	}
+
+	for _, frame := range origin.Frames() {
+		if frame.IsRuntime() {
+			continue
+		}
+		write(frame.Function)
+		write(path.Base(frame.File))
+		if !config.ignoreLines {
+			write(strconv.Itoa(frame.Line))
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
+
+// WithFingerprint overrides the fingerprint returned by Fingerprint for the error. When err is a
+// *Detail, a copy of it is returned; any other error is wrapped by a *Detail that takes the caller of
+// WithFingerprint as its origin.
+//
+// Parameters:
+//   - err: The error that receives the fingerprint.
+//   - fingerprint: The fingerprint of the error.
+//
+// Returns:
+//   - error: A *Detail with the fingerprint, or nil when err is nil.
+func WithFingerprint(err error, fingerprint string) error {
+	if err == nil {
+		return nil
+	}
+	detail := detailOf(err, 1)
+	detail.fingerprint = fingerprint
+	return detail
+}
+
+// NormalizeMessage replaces the variable parts of an error message, such as numbers, hexadecimal
+// values, UUIDs and quoted strings, by placeholders, so messages that only differ by their parameters
+// are equal.
+//
+// Parameters:
+//   - msg: The message to be normalized.
+//
+// Returns:
+//   - string: The normalized message.
+//
+// Example:
+//
+//	fmt.Println(NormalizeMessage(`user "john" not found after 3 attempts`)) // user "*" not found after # attempts
+func NormalizeMessage(msg string) string {
+	msg = quotedRegex.ReplaceAllString(msg, `"*"`)
+	msg = uuidRegex.ReplaceAllString(msg, "#")
+	msg = hexRegex.ReplaceAllString(msg, "#")
+	return numberRegex.ReplaceAllString(msg, "#")
+}
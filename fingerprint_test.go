@@ -0,0 +1,98 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+var errTestFingerprint = Define("TEST_FINGERPRINT", NotFound, "user {id} not found")
+
+func newTestFingerprintError(id int) error {
+	return Newf("user %d not found in %q", id, "tenant-a")
+}
+
+func newTestFingerprintDefinition(id int) error {
+	return errTestFingerprint.NewSkipCaller(1, id)
+}
+
+func TestFingerprint(t *testing.T) {
+	errLine1 := New("user not found")
+	errLine2 := New("user not found")
+
+	tests := []struct {
+		name  string
+		err1  error
+		err2  error
+		equal bool
+	}{
+		{"Same line with different params", newTestFingerprintError(1), newTestFingerprintError(2), true},
+		{"Same definition with different fields", newTestFingerprintDefinition(1), newTestFingerprintDefinition(2), true},
+		{"Different lines", errLine1, errLine2, false},
+		{"Wrapped errors keep the origin", Wrap(newTestFingerprintError(1), "loading"), Wrap(newTestFingerprintError(2), "loading"), true},
+		{"Overridden fingerprint", WithFingerprint(New("a"), "custom"), WithFingerprint(New("b"), "custom"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp1, fp2 := Fingerprint(tt.err1), Fingerprint(tt.err2)
+			if fp1 == "" || (fp1 == fp2) != tt.equal {
+				t.Errorf("Fingerprint() = %v and %v, want equal %v", fp1, fp2, tt.equal)
+			}
+		})
+	}
+}
+
+func TestFingerprintPlainError(t *testing.T) {
+	err := fmt.Errorf("reading %q: %w", "config.yaml", io.ErrUnexpectedEOF)
+	fingerprint := func() string { return Fingerprint(err) }
+
+	if fp1, fp2 := Fingerprint(err), fingerprint(); fp1 == "" || fp1 != fp2 {
+		t.Errorf("Fingerprint() = %v and %v from two call sites, want equal", fp1, fp2)
+	}
+	if Fingerprint(err) == Fingerprint(errors.New(err.Error())) {
+		t.Error("Fingerprint() should differ for errors of different types")
+	}
+	if Fingerprint(err) != Fingerprint(fmt.Errorf("reading %q: %w", "other.yaml", io.ErrUnexpectedEOF)) {
+		t.Error("Fingerprint() should ignore the variable parts of the message")
+	}
+}
+
+func TestFingerprintIgnoreLineNumbers(t *testing.T) {
+	err1 := New("user not found")
+	err2 := New("user not found")
+
+	if Fingerprint(err1, IgnoreLineNumbers()) != Fingerprint(err2, IgnoreLineNumbers()) {
+		t.Error("Fingerprint() should ignore line numbers with IgnoreLineNumbers")
+	}
+}
+
+func TestFingerprintOverrideInChain(t *testing.T) {
+	err := Wrap(WithFingerprint(errors.New("boom"), "custom"), "wrapping")
+	if got := Fingerprint(err); got != "custom" {
+		t.Errorf("Fingerprint() = %v, want custom", got)
+	}
+	if Fingerprint(nil) != "" {
+		t.Error("Fingerprint() should be empty for nil errors")
+	}
+}
+
+func TestNormalizeMessage(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{`user "john" not found after 3 attempts`, `user "*" not found after # attempts`},
+		{"request 123e4567-e89b-12d3-a456-426614174000 failed", "request # failed"},
+		{"bad pointer 0xc000012345 at 1.5s", "bad pointer # at #s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			if got := NormalizeMessage(tt.msg); got != tt.want {
+				t.Errorf("NormalizeMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
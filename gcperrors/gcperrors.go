@@ -0,0 +1,121 @@
+// Package gcperrors renders errors of the github.com/tech4works/errors package as entries understood by
+// Google Cloud Error Reporting, which only groups Go errors whose stack trace looks like a Go panic.
+package gcperrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/tech4works/errors"
+)
+
+// EventType is the value of the "@type" property that makes Cloud Logging forward an entry to Error
+// Reporting.
+const EventType = "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
+
+// ServiceContext identifies the service that reported the error.
+type ServiceContext struct {
+	Service string `json:"service"`
+	Version string `json:"version,omitempty"`
+}
+
+// Event is a reported error entry of Cloud Error Reporting.
+type Event struct {
+	Type           string         `json:"@type"`
+	Severity       string         `json:"severity"`
+	EventTime      time.Time      `json:"eventTime"`
+	Message        string         `json:"message"`
+	ServiceContext ServiceContext `json:"serviceContext"`
+	Context        EventContext   `json:"context"`
+}
+
+// EventContext holds the location where the error was reported.
+type EventContext struct {
+	ReportLocation ReportLocation `json:"reportLocation"`
+}
+
+// ReportLocation is the source location where the error was created.
+type ReportLocation struct {
+	FilePath     string `json:"filePath"`
+	LineNumber   int    `json:"lineNumber"`
+	FunctionName string `json:"functionName"`
+}
+
+// NewEvent builds the reported error entry of err. The message is the message of err followed by the
+// stack of the innermost *errors.Detail of its chain, where the error originated, in the format of a Go
+// panic, and the report location is the origin of that same Detail.
+//
+// Parameters:
+//   - err: The error to be reported.
+//   - service: The ServiceContext of the reporting service.
+//
+// Returns:
+//   - *Event: The reported error entry, or nil when err is nil.
+//
+// Example:
+//
+//	event := gcperrors.NewEvent(err, gcperrors.ServiceContext{Service: "billing", Version: "v1.4.0"})
+//	_ = json.NewEncoder(os.Stderr).Encode(event)
+func NewEvent(err error, service ServiceContext) *Event {
+	if err == nil {
+		return nil
+	}
+
+	origin := errors.Origin(err)
+	return &Event{
+		Type:           EventType,
+		Severity:       "ERROR",
+		EventTime:      time.Now().UTC(),
+		Message:        errors.Details(err).Message() + "\n\n" + PanicStack(origin),
+		ServiceContext: service,
+		Context: EventContext{
+			ReportLocation: ReportLocation{
+				FilePath:     origin.File(),
+				LineNumber:   origin.Line(),
+				FunctionName: origin.Func(),
+			},
+		},
+	}
+}
+
+// Write writes the reported error entry of err as a single JSON line, as expected by the Cloud Logging
+// agents that read the standard output of the service. Nothing is written when err is nil.
+//
+// Parameters:
+//   - w: The writer where the entry is written.
+//   - err: The error to be reported.
+//   - service: The ServiceContext of the reporting service.
+//
+// Returns:
+//   - error: An error if the entry could not be encoded or written.
+func Write(w io.Writer, err error, service ServiceContext) error {
+	event := NewEvent(err, service)
+	if event == nil {
+		return nil
+	}
+	return json.NewEncoder(w).Encode(event)
+}
+
+// PanicStack formats the frames of the Detail stack, starting at the frame where the error was created,
+// exactly as the runtime prints the stack of a panicking goroutine:
+//
+//	goroutine 1 [running]:
+//	main.main()
+//		/app/main.go:10 +0x0
+//
+// Parameters:
+//   - detail: The Detail whose stack is formatted.
+//
+// Returns:
+//   - string: The stack in the Go panic format.
+func PanicStack(detail *errors.Detail) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "goroutine %d [running]:\n", max(detail.GoroutineID(), 1))
+	for _, frame := range detail.Frames() {
+		fmt.Fprintf(&sb, "%s(...)\n\t%s:%d +0x0\n", frame.Function, frame.File, frame.Line)
+	}
+	return sb.String()
+}
@@ -0,0 +1,55 @@
+package gcperrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/tech4works/errors"
+)
+
+var panicStackRegex = regexp.MustCompile(`^goroutine \d+ \[running]:\n(\S+\(\.\.\.\)\n\t\S+:\d+ \+0x0\n)+$`)
+
+func TestNewEvent(t *testing.T) {
+	err := errors.Wrap(errors.New("connection refused"), "charging card")
+
+	event := NewEvent(err, ServiceContext{Service: "billing", Version: "v1"})
+	if event.Type != EventType || event.Severity != "ERROR" || event.ServiceContext.Service != "billing" {
+		t.Errorf("NewEvent() = %+v", event)
+	}
+
+	message, stack, ok := strings.Cut(event.Message, "\n\n")
+	if !ok || message != "charging card: connection refused" {
+		t.Errorf("NewEvent() message = %q", event.Message)
+	}
+	if !panicStackRegex.MatchString(stack) {
+		t.Errorf("NewEvent() stack is not in the panic format:\n%s", stack)
+	}
+	if strings.Contains(stack, "runtime/debug.Stack") || strings.Contains(stack, "tech4works/errors.New") {
+		t.Errorf("NewEvent() stack should start at the origin of the error:\n%s", stack)
+	}
+
+	location := event.Context.ReportLocation
+	if location.FunctionName != "TestNewEvent" || location.FilePath != "gcperrors/gcperrors_test.go" || location.LineNumber == 0 {
+		t.Errorf("NewEvent() report location = %+v", location)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, errors.New("boom"), ServiceContext{Service: "billing"}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil || entry["@type"] != EventType {
+		t.Errorf("Write() = %s, error = %v", buf.String(), err)
+	}
+
+	buf.Reset()
+	if err := Write(&buf, nil, ServiceContext{}); err != nil || buf.Len() != 0 {
+		t.Errorf("Write() should not write nil errors, got %q", buf.String())
+	}
+}
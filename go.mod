@@ -1,3 +1,5 @@
 module github.com/tech4works/errors
 
 go 1.22
+
+require gopkg.in/yaml.v3 v3.0.1
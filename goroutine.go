@@ -0,0 +1,91 @@
+package errors
+
+import (
+	"context"
+	"maps"
+	"runtime/pprof"
+	"strings"
+)
+
+// GoroutineID returns the ID of the goroutine that created the error, parsed from the header of its debug
+// stack or, for errors captured with the StackPCs mode, taken from their metadata when MetadataGoroutine
+// is enabled.
+//
+// Returns:
+//   - int64: The goroutine ID, or 0 when it is unknown.
+//
+// Example:
+//
+//	err := New("failed")
+//	fmt.Println(Details(err).GoroutineID()) // 1
+func (e *Detail) GoroutineID() int64 {
+	if id := parseGoroutineID(e.stack); id != 0 {
+		return id
+	}
+	if e.metadata != nil {
+		return e.metadata.GoroutineID
+	}
+	return 0
+}
+
+// GoroutineLabels returns the profiler labels of the goroutine that created the error, as set with
+// runtime/pprof.Do or pprof.WithLabels. The labels can only be read from a context, so they are recorded
+// by the constructors that receive one, such as NewCtx and WrapCtx.
+//
+// Returns:
+//   - map[string]string: A copy of the labels, or nil when there are none.
+//
+// Example:
+//
+//	pprof.Do(ctx, pprof.Labels("pool", "billing"), func(ctx context.Context) {
+//		err := NewCtx(ctx, "failed")
+//		fmt.Println(Details(err).GoroutineLabels()) // map[pool:billing]
+//	})
+func (e *Detail) GoroutineLabels() map[string]string {
+	return maps.Clone(e.labels)
+}
+
+// CreatedBy returns the frame of the "go" statement that started the goroutine that created the error,
+// parsed from the "created by" line of its debug stack. The main goroutine and errors captured with the
+// StackPCs mode have no such frame.
+//
+// Returns:
+//   - Frame: The frame that started the goroutine, whose Function is the function that ran the "go"
+//     statement.
+//   - bool: A boolean value indicating whether the frame was found.
+//
+// Example:
+//
+//	go func() {
+//		err := New("failed")
+//		frame, _ := Details(err).CreatedBy()
+//		fmt.Println(frame.Function) // main.main
+//	}()
+func (e *Detail) CreatedBy() (Frame, bool) {
+	lines := strings.Split(strings.ReplaceAll(e.stack, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines)-1; i++ {
+		function, ok := strings.CutPrefix(strings.TrimSpace(lines[i]), "created by ")
+		if !ok || !strings.HasPrefix(lines[i+1], "\t") {
+			continue
+		}
+		if j := strings.Index(function, " in goroutine "); j >= 0 {
+			function = function[:j]
+		}
+		file, line := parseLocation(strings.TrimSpace(lines[i+1]))
+		return Frame{Function: function, File: file, Line: line}, true
+	}
+	return Frame{}, false
+}
+
+// goroutineLabels returns the profiler labels of ctx, or nil when there are none.
+func goroutineLabels(ctx context.Context) map[string]string {
+	var labels map[string]string
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[key] = value
+		return true
+	})
+	return labels
+}
@@ -0,0 +1,83 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"runtime/pprof"
+	"strings"
+	"testing"
+)
+
+func TestDetail_GoroutineID(t *testing.T) {
+	tests := []struct {
+		name   string
+		detail *Detail
+		want   int64
+	}{
+		{"Debug stack", &Detail{stack: testStack}, 7},
+		{"Metadata", &Detail{stack: "pcs anchor=0x1", metadata: &Metadata{GoroutineID: 9}}, 9},
+		{"Unknown", &Detail{stack: "pcs anchor=0x1"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.detail.GoroutineID(); got != tt.want {
+				t.Errorf("Detail.GoroutineID() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if id := Details(New("test error")).GoroutineID(); id <= 0 {
+		t.Errorf("Detail.GoroutineID() = %d, want a goroutine ID", id)
+	}
+}
+
+func TestDetail_CreatedBy(t *testing.T) {
+	frame, ok := Details(New("test error")).CreatedBy()
+	if !ok || frame.Function != "testing.(*T).Run" || !strings.HasSuffix(frame.File, "testing.go") || frame.Line == 0 {
+		t.Errorf("Detail.CreatedBy() = %+v, %v", frame, ok)
+	}
+
+	done := make(chan *Detail)
+	go func() {
+		done <- Details(New("test error"))
+	}()
+	frame, ok = (<-done).CreatedBy()
+	if !ok || frame.Function != "github.com/tech4works/errors.TestDetail_CreatedBy" || !strings.HasSuffix(frame.File, "goroutine_test.go") {
+		t.Errorf("Detail.CreatedBy() = %+v, %v", frame, ok)
+	}
+
+	if _, ok = (&Detail{stack: "goroutine 1 [running]:\nmain.main()\n\t/app/main.go:5 +0x1\n"}).CreatedBy(); ok {
+		t.Error("Detail.CreatedBy() should not find a frame for the main goroutine")
+	}
+}
+
+func TestDetail_GoroutineLabels(t *testing.T) {
+	var err error
+	pprof.Do(context.Background(), pprof.Labels("pool", "billing", "worker", "3"), func(ctx context.Context) {
+		err = NewCtx(ctx, "test error")
+	})
+	detail := Details(err)
+
+	want := map[string]string{"pool": "billing", "worker": "3"}
+	if got := detail.GoroutineLabels(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Detail.GoroutineLabels() = %v, want %v", got, want)
+	}
+	if got := Details(New("test error")).GoroutineLabels(); got != nil {
+		t.Errorf("Detail.GoroutineLabels() = %v, want nil", got)
+	}
+
+	bs, _ := json.Marshal(detail)
+	var decoded Detail
+	if jsonErr := json.Unmarshal(bs, &decoded); jsonErr != nil || !reflect.DeepEqual(decoded.GoroutineLabels(), want) {
+		t.Errorf("decoded GoroutineLabels() = %v, %v", decoded.GoroutineLabels(), jsonErr)
+	}
+
+	var sb strings.Builder
+	slog.New(slog.NewTextHandler(&sb, nil)).Error("failed", "err", detail)
+	if !strings.Contains(sb.String(), "err.goroutine_labels.pool=billing err.goroutine_labels.worker=3") {
+		t.Errorf("Detail.LogValue() = %s", sb.String())
+	}
+}
@@ -0,0 +1,182 @@
+package errors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// graphNode is an error of the tree of an error.
+type graphNode struct {
+	id    string
+	lines []string
+}
+
+// graphEdge links an error to an error wrapped by it, labeled "wraps" for the errors that wrap a
+// single error and "joins" for the errors that wrap multiple errors, such as the ones returned by the
+// standard library errors.Join.
+type graphEdge struct {
+	from, to string
+	label    string
+}
+
+// ToDOT renders the tree of err, formed by the errors it wraps, in the Graphviz DOT language. Every
+// error is a node labeled with its code, message, function and origin, when present, and every edge
+// points from an error to the errors it wraps, labeled "wraps" or "joins".
+//
+// Parameters:
+//   - err: The root error of the tree.
+//
+// Returns:
+//   - string: The DOT digraph, or an empty string when err is nil.
+//
+// Example:
+//
+//	err := Wrap(stderrors.Join(fetchA(), fetchB()), "running batch")
+//	_ = os.WriteFile("errors.dot", []byte(ToDOT(err)), 0o644)
+//	// dot -Tsvg errors.dot > errors.svg
+func ToDOT(err error) string {
+	if err == nil {
+		return ""
+	}
+	nodes, edges := errorGraph(err)
+
+	var sb strings.Builder
+	sb.WriteString("digraph errors {\n")
+	sb.WriteString("  node [shape=box, fontname=\"monospace\"];\n")
+	for _, node := range nodes {
+		sb.WriteString(fmt.Sprintf("  %s [label=%s];\n", node.id, dotString(strings.Join(node.lines, "\n"))))
+	}
+	for _, edge := range edges {
+		style := ""
+		if edge.label == "joins" {
+			style = ", style=dashed"
+		}
+		sb.WriteString(fmt.Sprintf("  %s -> %s [label=%q%s];\n", edge.from, edge.to, edge.label, style))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// ToMermaid renders the tree of err, formed by the errors it wraps, as a Mermaid flowchart, with the
+// same nodes and edges of ToDOT. The result can be embedded in Markdown documents in a "mermaid" code
+// block.
+//
+// Parameters:
+//   - err: The root error of the tree.
+//
+// Returns:
+//   - string: The Mermaid flowchart, or an empty string when err is nil.
+func ToMermaid(err error) string {
+	if err == nil {
+		return ""
+	}
+	nodes, edges := errorGraph(err)
+
+	var sb strings.Builder
+	sb.WriteString("flowchart TD\n")
+	for _, node := range nodes {
+		lines := make([]string, len(node.lines))
+		for i, line := range node.lines {
+			lines[i] = mermaidString(line)
+		}
+		sb.WriteString(fmt.Sprintf("  %s[\"%s\"]\n", node.id, strings.Join(lines, "<br/>")))
+	}
+	for _, edge := range edges {
+		arrow := "-->"
+		if edge.label == "joins" {
+			arrow = "-.->"
+		}
+		sb.WriteString(fmt.Sprintf("  %s %s|%s| %s\n", edge.from, arrow, edge.label, edge.to))
+	}
+	return sb.String()
+}
+
+// errorGraph collects the nodes and edges of the tree of err, in depth-first order.
+func errorGraph(err error) ([]graphNode, []graphEdge) {
+	var nodes []graphNode
+	var edges []graphEdge
+
+	var visit func(err error, parent, label string)
+	visit = func(err error, parent, label string) {
+		id := "n" + strconv.Itoa(len(nodes))
+		nodes = append(nodes, graphNode{id: id, lines: nodeLines(err)})
+		if parent != "" {
+			edges = append(edges, graphEdge{from: parent, to: id, label: label})
+		}
+
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			if cause := x.Unwrap(); cause != nil {
+				visit(cause, id, "wraps")
+			}
+		case interface{ Unwrap() []error }:
+			for _, cause := range x.Unwrap() {
+				if cause != nil {
+					visit(cause, id, "joins")
+				}
+			}
+		}
+	}
+	visit(err, "", "")
+	return nodes, edges
+}
+
+// nodeLines returns the label lines of an error: its code, own message, function and origin for the
+// detailed errors, or its type and own message for the others.
+func nodeLines(err error) []string {
+	detail, ok := err.(*Detail)
+	if !ok {
+		if msg := ownMessage(err); msg != "" {
+			return []string{typeOf(err), msg}
+		}
+		return []string{typeOf(err)}
+	}
+
+	var lines []string
+	if detail.code != "" {
+		lines = append(lines, detail.code)
+	}
+	if detail.message != "" {
+		lines = append(lines, detail.message)
+	}
+	if detail.funcName != "" {
+		lines = append(lines, detail.funcName)
+	}
+	return append(lines, detail.file+":"+detail.line)
+}
+
+// ownMessage returns the message of err without the messages of the errors it wraps, which errors
+// created with fmt.Errorf usually append after a colon and errors created with the standard library
+// errors.Join are made of.
+func ownMessage(err error) string {
+	msg := err.Error()
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		if cause := x.Unwrap(); cause != nil {
+			msg = strings.TrimSuffix(msg, ": "+cause.Error())
+		}
+	case interface{ Unwrap() []error }:
+		var causes []string
+		for _, cause := range x.Unwrap() {
+			if cause != nil {
+				causes = append(causes, cause.Error())
+			}
+		}
+		if msg == strings.Join(causes, "\n") {
+			msg = ""
+		}
+	}
+	return cleanMessage(msg)
+}
+
+// dotString quotes s as a DOT string, where line breaks are written as "\n".
+func dotString(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
+
+// mermaidString escapes s to be used in a quoted Mermaid label.
+func mermaidString(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
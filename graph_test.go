@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestToDOT(t *testing.T) {
+	def := Define("GRAPH_FETCH_FAILED", Unavailable, "fetch {name} failed")
+	fetchA := def.New("a")
+	err := Wrap(errors.Join(fetchA, fmt.Errorf("fetch b: %w", io.EOF)), "running batch")
+	origin := fmt.Sprintf("%s:%d", Details(fetchA).File(), Details(fetchA).Line())
+
+	got := ToDOT(err)
+	want := []string{
+		"digraph errors {\n",
+		`  n0 [label="GRAPH_FETCH_FAILED\nrunning batch\nTestToDOT\n`,
+		`  n1 [label="*errors.joinError"];`,
+		`  n2 [label="GRAPH_FETCH_FAILED\nfetch a failed\nTestToDOT\n` + origin + `"];`,
+		`  n3 [label="*fmt.wrapError\nfetch b"];`,
+		`  n4 [label="*errors.errorString\nEOF"];`,
+		`  n0 -> n1 [label="wraps"];`,
+		`  n1 -> n2 [label="joins", style=dashed];`,
+		`  n1 -> n3 [label="joins", style=dashed];`,
+		`  n3 -> n4 [label="wraps"];`,
+	}
+	for _, s := range want {
+		if !strings.Contains(got, s) {
+			t.Errorf("ToDOT() does not contain %q:\n%s", s, got)
+		}
+	}
+	if strings.Index(got, "n0 -> n1") > strings.Index(got, "n1 -> n2") {
+		t.Errorf("ToDOT() edges are not in depth-first order:\n%s", got)
+	}
+
+	if got = ToDOT(errors.New(`say "hi"`)); !strings.Contains(got, `n0 [label="*errors.errorString\nsay \"hi\""];`) {
+		t.Errorf("ToDOT() does not escape the label:\n%s", got)
+	}
+	if ToDOT(nil) != "" {
+		t.Error("ToDOT(nil) should be empty")
+	}
+}
+
+func TestToMermaid(t *testing.T) {
+	err := Wrap(errors.Join(New("a <failed>"), errors.New(`b "failed"`)), "running batch")
+
+	got := ToMermaid(err)
+	want := []string{
+		"flowchart TD\n",
+		`  n0["running batch<br/>TestToMermaid<br/>`,
+		`  n1["*errors.joinError"]`,
+		`  n2["a #lt;failed#gt;<br/>TestToMermaid<br/>`,
+		`  n3["*errors.errorString<br/>b #quot;failed#quot;"]`,
+		"  n0 -->|wraps| n1\n",
+		"  n1 -.->|joins| n2\n",
+		"  n1 -.->|joins| n3\n",
+	}
+	for _, s := range want {
+		if !strings.Contains(got, s) {
+			t.Errorf("ToMermaid() does not contain %q:\n%s", s, got)
+		}
+	}
+	if ToMermaid(nil) != "" {
+		t.Error("ToMermaid(nil) should be empty")
+	}
+}
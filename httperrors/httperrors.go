@@ -0,0 +1,159 @@
+// Package httperrors writes the errors of the github.com/tech4works/errors package as HTTP responses.
+//
+// Errors are written as problem details (RFC 9457) documents, which are safe to be returned to the
+// clients. During development, requests made by a browser get an HTML page instead, with the message,
+// the cause chain, the fields, the stack frames with their source and the request of the error. The
+// development page is only served when the development mode is enabled, explicitly with SetDevMode or
+// by the environment, so it is disabled in production by default.
+package httperrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"sync/atomic"
+
+	"github.com/tech4works/errors"
+)
+
+// HandlerFunc is an HTTP handler that returns an error, which is written to the response with Write.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP implements http.Handler, calling f and writing its error, if any.
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f(w, r); err != nil {
+		Write(w, r, err)
+	}
+}
+
+// devEnvVars are the environment variables checked, in order, for the environment of the application.
+var devEnvVars = []string{"ERRORS_ENV", "APP_ENV", "GO_ENV"}
+
+// devMode holds the mode set by SetDevMode: 0 when it was not set, 1 when enabled and 2 when disabled.
+var devMode atomic.Int32
+
+// SetDevMode enables or disables the development mode, overriding the environment.
+//
+// Parameters:
+//   - enabled: A boolean value indicating whether the development page is served.
+func SetDevMode(enabled bool) {
+	if enabled {
+		devMode.Store(1)
+	} else {
+		devMode.Store(2)
+	}
+}
+
+// DevMode reports whether the development mode is enabled. Unless it was set with SetDevMode, the mode is
+// enabled when the first of the ERRORS_ENV, APP_ENV and GO_ENV environment variables that is set holds
+// "development", "dev" or "local".
+//
+// Returns:
+//   - bool: A boolean value indicating whether the development page is served.
+func DevMode() bool {
+	switch devMode.Load() {
+	case 1:
+		return true
+	case 2:
+		return false
+	}
+	for _, name := range devEnvVars {
+		if env, ok := os.LookupEnv(name); ok {
+			switch strings.ToLower(env) {
+			case "development", "dev", "local":
+				return true
+			default:
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// Write writes err as the response of the request, with the status of its problem details. In
+// development mode, requests that accept HTML get the development page; otherwise the response is the
+// problem details document of the error, with the "application/problem+json" content type.
+//
+// Parameters:
+//   - w: The response writer.
+//   - r: The request that failed.
+//   - err: The error to be written.
+//
+// Example:
+//
+//	http.Handle("/users/", httperrors.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//		user, err := users.Find(r.Context(), r.PathValue("id"))
+//		if err != nil {
+//			return err
+//		}
+//		return json.NewEncoder(w).Encode(user)
+//	}))
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	if err == nil {
+		return
+	}
+	problem := errors.ToProblem(err)
+	problem.Instance = r.URL.Path
+
+	if DevMode() && strings.Contains(r.Header.Get("Accept"), "text/html") {
+		if page, pageErr := renderPage(r, err, problem); pageErr == nil {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Header().Set("Cache-Control", "no-store")
+			w.WriteHeader(problem.Status)
+			_, _ = w.Write(page)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+// Recover is a middleware that recovers the panics of next, writing them as errors with Write. The
+// panic value is turned into an error whose origin is the function that panicked; a panic value that is
+// an error is wrapped, so it can still be matched with errors.Is and errors.As and its chain is shown.
+//
+// Parameters:
+//   - next: The handler to be protected.
+//
+// Returns:
+//   - http.Handler: The handler that recovers the panics of next.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil || v == http.ErrAbortHandler {
+				if v != nil {
+					panic(v)
+				}
+				return
+			}
+			if err, ok := v.(error); ok {
+				Write(w, r, errors.WrapSkipCaller(panicSkip(), err, "panic"))
+			} else {
+				Write(w, r, errors.NewSkipCaller(panicSkip(), "panic:", fmt.Sprint(v)))
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// panicSkip returns the NewSkipCaller value that identifies the function that panicked, when called by a
+// deferred function, which is called by the runtime panic handling above that function.
+func panicSkip() int {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for i := 1; ; i++ {
+		frame, more := frames.Next()
+		if frame.Function == "runtime.gopanic" || frame.Function == "runtime.sigpanic" {
+			return i + 2
+		}
+		if !more {
+			return 1
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package httperrors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/tech4works/errors"
+)
+
+var errOrderLocked = errors.Define("HTTP_ORDER_LOCKED", errors.FailedPrecondition, "order {id} is locked",
+	errors.HTTPStatus(http.StatusLocked), errors.PublicMessage("The order is being edited by someone else."),
+	errors.Hint("retry once the order is saved"))
+
+func TestDevMode(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		set  *bool
+		want bool
+	}{
+		{"No environment", nil, nil, false},
+		{"Development environment", map[string]string{"ERRORS_ENV": "development"}, nil, true},
+		{"Local app environment", map[string]string{"APP_ENV": "local"}, nil, true},
+		{"Production environment", map[string]string{"ERRORS_ENV": "production", "APP_ENV": "dev"}, nil, false},
+		{"Enabled explicitly", map[string]string{"ERRORS_ENV": "production"}, ptr(true), true},
+		{"Disabled explicitly", map[string]string{"ERRORS_ENV": "dev"}, ptr(false), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range devEnvVars {
+				t.Setenv(name, "")
+				_ = os.Unsetenv(name)
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			t.Cleanup(func() { devMode.Store(0) })
+			if tt.set != nil {
+				SetDevMode(*tt.set)
+			}
+
+			if got := DevMode(); got != tt.want {
+				t.Errorf("DevMode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	err := errors.Wrap(errOrderLocked.New(7), "loading order")
+	_, _, line, _ := runtime.Caller(0)
+
+	tests := []struct {
+		name        string
+		dev         bool
+		accept      string
+		contentType string
+		contains    []string
+		excludes    []string
+	}{
+		{
+			name:        "Production",
+			accept:      "text/html",
+			contentType: "application/problem+json",
+			contains:    []string{`"code":"HTTP_ORDER_LOCKED"`, `"detail":"The order is being edited by someone else."`, `"instance":"/orders/7"`},
+			excludes:    []string{"loading order"},
+		},
+		{
+			name:        "Development API client",
+			dev:         true,
+			accept:      "application/json",
+			contentType: "application/problem+json",
+			contains:    []string{`"status":423`},
+		},
+		{
+			name:        "Development browser",
+			dev:         true,
+			accept:      "text/html,application/xhtml+xml",
+			contentType: "text/html; charset=utf-8",
+			contains: []string{
+				"<h1>loading order: order 7 is locked</h1>",
+				"423 · HTTP_ORDER_LOCKED (FAILED_PRECONDITION)",
+				"<li>retry once the order is saved</li>",
+				`<th class="mono">id</th><td class="mono">7</td>`,
+				"<summary>loading order <code>HTTP_ORDER_LOCKED</code></summary>",
+				"<summary>order 7 is locked <code>HTTP_ORDER_LOCKED</code></summary>",
+				fmt.Sprintf(`<span class="current">%5d  	err := errors.Wrap(errOrderLocked.New(7), &#34;loading order&#34;)</span>`, line-1),
+				`<th class="mono">Authorization</th><td class="mono">[redacted]</td>`,
+				"Copy as JSON",
+				`const errorJSON = "{\n`,
+			},
+			excludes: []string{"secret-token"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetDevMode(tt.dev)
+			t.Cleanup(func() { devMode.Store(0) })
+
+			req := httptest.NewRequest(http.MethodGet, "/orders/7", nil)
+			req.Header.Set("Accept", tt.accept)
+			req.Header.Set("Authorization", "Bearer secret-token")
+			rec := httptest.NewRecorder()
+			Write(rec, req, err)
+
+			if rec.Code != http.StatusLocked {
+				t.Errorf("Write() status = %d, want %d", rec.Code, http.StatusLocked)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Write() Content-Type = %q, want %q", got, tt.contentType)
+			}
+			body := rec.Body.String()
+			for _, s := range tt.contains {
+				if !strings.Contains(body, s) {
+					t.Errorf("Write() body does not contain %q:\n%s", s, body)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(body, s) {
+					t.Errorf("Write() body contains %q", s)
+				}
+			}
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	err := errors.Wrap(stderrors.Join(stderrors.New("cache unavailable"), errOrderLocked.New(8)), "loading orders")
+
+	var got []string
+	for _, link := range chain(err) {
+		got = append(got, link.Message)
+	}
+	want := []string{"loading orders", "cache unavailable order 8 is locked", "cache unavailable", "order 8 is locked"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("chain() = %q, want %q", got, want)
+	}
+}
+
+func TestHandlerFunc(t *testing.T) {
+	handler := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		if r.URL.Path == "/ok" {
+			_, _ = w.Write([]byte("ok"))
+			return nil
+		}
+		return errOrderLocked.New(1)
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Errorf("HandlerFunc.ServeHTTP() = %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+	var problem errors.Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil || rec.Code != http.StatusLocked ||
+		problem.Code != "HTTP_ORDER_LOCKED" {
+		t.Errorf("HandlerFunc.ServeHTTP() = %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRecover(t *testing.T) {
+	SetDevMode(true)
+	t.Cleanup(func() { devMode.Store(0) })
+
+	var line int
+	handler := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, line, _ = runtime.Caller(0)
+		panic("boom")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "text/html")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Recover() status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if want := fmt.Sprintf("httperrors/httperrors_test.go:%d func", line+1); !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("Recover() body does not contain %q:\n%s", want, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "panic: boom") {
+		t.Errorf("Recover() body does not contain the panic value")
+	}
+}
+
+func TestRecoverError(t *testing.T) {
+	var line int
+	handler := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, line, _ = runtime.Caller(0)
+		panic(fmt.Errorf("charging: %w", errOrderLocked.New(7)))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var problem errors.Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Recover() body is not a problem: %v\n%s", err, rec.Body.String())
+	}
+	if rec.Code != http.StatusLocked || problem.Code != "HTTP_ORDER_LOCKED" || problem.Params["id"] != float64(7) {
+		t.Errorf("Recover() = %d %+v, want the problem of the panicked error", rec.Code, problem)
+	}
+
+	SetDevMode(true)
+	t.Cleanup(func() { devMode.Store(0) })
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "text/html")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	for _, want := range []string{fmt.Sprintf("httperrors/httperrors_test.go:%d func", line+1), "panic: charging: order 7 is locked"} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("Recover() body does not contain %q:\n%s", want, rec.Body.String())
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
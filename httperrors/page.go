@@ -0,0 +1,235 @@
+package httperrors
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+
+	"github.com/tech4works/errors"
+)
+
+// sourceLines is the number of source lines shown before and after the line of the application frames.
+const sourceLines = 5
+
+// redactedHeaders are the request headers whose values are not shown by the development page.
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Cookie":              true,
+	"Proxy-Authorization": true,
+	"X-Api-Key":           true,
+}
+
+type pageData struct {
+	Problem *errors.Problem
+	Message string
+	Detail  *errors.Detail
+	Chain   []pageLink
+	Fields  []pageField
+	Frames  []pageFrame
+	Request pageRequest
+	JSON    string
+}
+
+type pageLink struct {
+	Message string
+	Origin  string
+	Code    string
+	Type    string
+	Fields  []pageField
+}
+
+type pageField struct {
+	Key   string
+	Value string
+}
+
+type pageFrame struct {
+	errors.Frame
+	App    bool
+	Source []errors.SourceLine
+}
+
+type pageRequest struct {
+	Method     string
+	URL        string
+	Proto      string
+	RemoteAddr string
+	Headers    []pageField
+}
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Problem.Status }} {{ .Message }}</title>
+<style>
+body { font-family: sans-serif; font-size: 14px; margin: 0; color: #222; }
+header { background: #b3261e; color: #fff; padding: 1em 2em; }
+header h1 { margin: 0 0 .3em; font-size: 20px; }
+header .meta { font-family: monospace; opacity: .85; }
+main { padding: 1em 2em; }
+h2 { font-size: 16px; border-bottom: 1px solid #ddd; padding-bottom: 4px; }
+table { border-collapse: collapse; }
+th, td { border-bottom: 1px solid #eee; padding: 4px 8px; text-align: left; vertical-align: top; }
+code, .mono { font-family: monospace; }
+details { margin: 4px 0; }
+summary { cursor: pointer; }
+.dim { color: #777; }
+.frame.std summary { color: #777; }
+pre.source { background: #f6f6f6; margin: 4px 0 8px 1.5em; padding: 4px 0; font-size: 12px; }
+pre.source span { display: block; padding: 0 8px; }
+pre.source .current { background: #fde2e1; font-weight: bold; }
+button { cursor: pointer; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{ .Message }}</h1>
+<div class="meta">{{ .Problem.Status }}{{ with .Detail.Code }} · {{ . }} ({{ $.Detail.Kind }}){{ end }} · {{ .Detail.File }}:{{ .Detail.Line }} {{ .Detail.Func }}</div>
+</header>
+<main>
+<p><button id="copy" type="button">Copy as JSON</button> <span class="dim">This page is only served in development mode.</span></p>
+{{- with .Detail.Hints }}
+<h2>Hints</h2>
+<ul>{{ range . }}<li>{{ . }}</li>{{ end }}</ul>
+{{- end }}
+{{- with .Detail.DocsURL }}
+<p>Documentation: <a href="{{ . }}">{{ . }}</a></p>
+{{- end }}
+{{- if .Fields }}
+<h2>Fields</h2>
+<table>
+{{- range .Fields }}
+<tr><th class="mono">{{ .Key }}</th><td class="mono">{{ .Value }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+<h2>Cause chain</h2>
+{{- range $i, $link := .Chain }}
+<details{{ if eq $i 0 }} open{{ end }}>
+<summary>{{ $link.Message }}{{ with $link.Code }} <code>{{ . }}</code>{{ end }}</summary>
+<table>
+{{- with $link.Origin }}<tr><th>Origin</th><td class="mono">{{ . }}</td></tr>{{ end }}
+{{- with $link.Type }}<tr><th>Type</th><td class="mono">{{ . }}</td></tr>{{ end }}
+{{- range $link.Fields }}<tr><th class="mono">{{ .Key }}</th><td class="mono">{{ .Value }}</td></tr>{{ end }}
+</table>
+</details>
+{{- end }}
+<h2>Stack</h2>
+{{- range .Frames }}
+<details class="frame{{ if not .App }} std{{ end }}"{{ if .Source }} open{{ end }}>
+<summary><code>{{ .Function }}</code> <span class="dim mono">{{ .File }}:{{ .Line }}</span></summary>
+{{- if .Source }}
+<pre class="source">{{ range .Source }}<span{{ if .Current }} class="current"{{ end }}>{{ printf "%5d" .Number }}  {{ .Text }}</span>{{ end }}</pre>
+{{- end }}
+</details>
+{{- end }}
+<h2>Request</h2>
+<table>
+<tr><th>Method</th><td class="mono">{{ .Request.Method }}</td></tr>
+<tr><th>URL</th><td class="mono">{{ .Request.URL }}</td></tr>
+<tr><th>Protocol</th><td class="mono">{{ .Request.Proto }}</td></tr>
+<tr><th>Remote address</th><td class="mono">{{ .Request.RemoteAddr }}</td></tr>
+{{- range .Request.Headers }}
+<tr><th class="mono">{{ .Key }}</th><td class="mono">{{ .Value }}</td></tr>
+{{- end }}
+</table>
+</main>
+<script>
+const errorJSON = {{ .JSON }};
+document.getElementById("copy").addEventListener("click", function () {
+  navigator.clipboard.writeText(errorJSON).then(() => { this.textContent = "Copied"; });
+});
+</script>
+</body>
+</html>
+`))
+
+// renderPage renders the development page of err.
+func renderPage(r *http.Request, err error, problem *errors.Problem) ([]byte, error) {
+	detail := errors.Details(err)
+	encoded, jsonErr := json.MarshalIndent(detail, "", "  ")
+	if jsonErr != nil {
+		return nil, jsonErr
+	}
+
+	data := pageData{
+		Problem: problem,
+		Message: detail.Message(),
+		Detail:  detail,
+		Chain:   chain(err),
+		Fields:  fields(detail.Fields()),
+		Frames:  frames(errors.Origin(err).Frames()),
+		Request: request(r),
+		JSON:    string(encoded),
+	}
+
+	var buf bytes.Buffer
+	if err = pageTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// chain lists err and every error wrapped by it, in depth-first order, with the own message of the
+// detailed errors.
+func chain(err error) []pageLink {
+	var links []pageLink
+	errors.Walk(err, func(err error, _ int) bool {
+		link := pageLink{Message: errors.MessageOf(err), Type: fmt.Sprintf("%T", err)}
+		if detail, ok := err.(*errors.Detail); ok {
+			link = pageLink{
+				Message: cmp.Or(detail.OwnMessage(), "(no message)"),
+				Origin:  fmt.Sprintf("%s:%d %s", detail.File(), detail.Line(), detail.Func()),
+				Code:    detail.Code(),
+				Fields:  fields(detail.Fields()),
+			}
+		}
+		links = append(links, link)
+		return false
+	})
+	return links
+}
+
+func fields(f errors.Fields) []pageField {
+	result := make([]pageField, 0, len(f))
+	for key, value := range f {
+		result = append(result, pageField{Key: key, Value: fmt.Sprint(value)})
+	}
+	sort.Slice(result, func(i, k int) bool {
+		return result[i].Key < result[k].Key
+	})
+	return result
+}
+
+func frames(stack []errors.Frame) []pageFrame {
+	result := make([]pageFrame, 0, len(stack))
+	for _, frame := range stack {
+		f := pageFrame{Frame: frame, App: !frame.IsStandard()}
+		if f.App {
+			f.Source, _ = frame.Source(sourceLines)
+		}
+		result = append(result, f)
+	}
+	return result
+}
+
+func request(r *http.Request) pageRequest {
+	req := pageRequest{Method: r.Method, URL: r.URL.String(), Proto: r.Proto, RemoteAddr: r.RemoteAddr}
+	for name, values := range r.Header {
+		for _, value := range values {
+			if redactedHeaders[name] {
+				value = "[redacted]"
+			}
+			req.Headers = append(req.Headers, pageField{Key: name, Value: value})
+		}
+	}
+	sort.SliceStable(req.Headers, func(i, k int) bool {
+		return req.Headers[i].Key < req.Headers[k].Key
+	})
+	return req
+}
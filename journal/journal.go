@@ -0,0 +1,311 @@
+// Package journal appends errors of the github.com/tech4works/errors package to a local JSON Lines file,
+// with size and time based rotation and optional compression of the rotated segments, and reads them
+// back. It is meant for processes that cannot always reach a centralized error tracker, such as edge
+// devices, so the errors can be collected locally and shipped later.
+package journal
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tech4works/errors"
+)
+
+// Options configures the rotation of a Journal.
+type Options struct {
+	// MaxSize is the size, in bytes, after which the current segment is rotated. Zero disables the
+	// size based rotation.
+	MaxSize int64
+	// MaxAge is the duration after which the current segment is rotated, counted from its first record,
+	// or from the moment it was created. Zero disables the time based rotation.
+	MaxAge time.Duration
+	// Compress enables the gzip compression of the rotated segments.
+	Compress bool
+	// MaxSegments is the number of rotated segments kept, the oldest ones are removed. Zero keeps them
+	// all.
+	MaxSegments int
+}
+
+// Record is a line of the journal.
+type Record struct {
+	Time        time.Time      `json:"time"`
+	Fingerprint string         `json:"fingerprint"`
+	Error       *errors.Detail `json:"error"`
+}
+
+// Journal appends errors to a JSON Lines file. It is safe for concurrent use and implements
+// errors.Reporter.
+type Journal struct {
+	mu     sync.Mutex
+	path   string
+	opts   Options
+	file   *os.File
+	size   int64
+	opened time.Time
+	now    func() time.Time
+}
+
+// Open opens the journal at path, creating the file and its directory when needed. Records are appended
+// to the existing file.
+//
+// Parameters:
+//   - path: The path of the current segment of the journal, e.g. "/var/lib/app/errors.jsonl".
+//   - opts: The rotation Options.
+//
+// Returns:
+//   - *Journal: The opened journal.
+//   - error: An error if the file could not be opened.
+//
+// Example:
+//
+//	j, err := journal.Open("/var/lib/app/errors.jsonl", journal.Options{MaxSize: 10 << 20, Compress: true})
+//	if err != nil {
+//		return err
+//	}
+//	defer j.Close()
+//
+//	errors.RegisterReporter(j)
+func Open(path string, opts Options) (*Journal, error) {
+	j := &Journal{path: path, opts: opts, now: time.Now}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	if err := j.open(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// Report appends err to the journal, ignoring write failures. Nil errors are ignored.
+func (j *Journal) Report(err error) {
+	_ = j.Write(err)
+}
+
+// Write appends err to the journal as a Record, rotating the current segment first when it reached
+// the limits of the Options.
+//
+// Parameters:
+//   - err: The error to be written.
+//
+// Returns:
+//   - error: An error if the record could not be encoded, written or rotated.
+func (j *Journal) Write(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	line, marshalErr := json.Marshal(Record{
+		Time:        j.now().UTC(),
+		Fingerprint: errors.Fingerprint(err),
+		Error:       errors.Details(err),
+	})
+	if marshalErr != nil {
+		return marshalErr
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file == nil {
+		return os.ErrClosed
+	}
+	if j.shouldRotate(int64(len(line))) {
+		if rotateErr := j.rotate(); rotateErr != nil {
+			return rotateErr
+		}
+	}
+
+	n, writeErr := j.file.Write(line)
+	j.size += int64(n)
+	return writeErr
+}
+
+// Rotate closes the current segment, renames it with its rotation timestamp, compressing it when
+// enabled, and opens a new one.
+//
+// Returns:
+//   - error: An error if the segment could not be rotated.
+func (j *Journal) Rotate() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file == nil {
+		return os.ErrClosed
+	}
+	return j.rotate()
+}
+
+// Close closes the current segment of the journal.
+//
+// Returns:
+//   - error: An error if the file could not be closed.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+func (j *Journal) open() error {
+	file, err := os.OpenFile(j.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	j.file = file
+	j.size = info.Size()
+	j.opened = j.now()
+	if j.size > 0 {
+		j.opened = segmentStart(j.path, info.ModTime())
+	}
+	return nil
+}
+
+// segmentStart returns the time of the first record of the segment at path, so the age of a segment
+// appended to after a restart is not reset, or fallback when the record cannot be read.
+func segmentStart(path string, fallback time.Time) time.Time {
+	start := fallback
+	readSegment(path, func(record Record, err error) bool {
+		if err == nil && !record.Time.IsZero() {
+			start = record.Time
+		}
+		return false
+	})
+	return start
+}
+
+func (j *Journal) shouldRotate(n int64) bool {
+	if j.size == 0 {
+		return false
+	}
+	if j.opts.MaxSize > 0 && j.size+n > j.opts.MaxSize {
+		return true
+	}
+	return j.opts.MaxAge > 0 && j.now().Sub(j.opened) >= j.opts.MaxAge
+}
+
+// rotate renames the current segment and opens a new one. The current segment is reopened whatever step
+// fails, so the journal keeps accepting records.
+func (j *Journal) rotate() error {
+	err := j.file.Close()
+	j.file = nil
+	if err == nil {
+		err = j.archive()
+	}
+	if openErr := j.open(); openErr != nil {
+		return openErr
+	}
+	return err
+}
+
+// archive renames the closed current segment with its rotation timestamp, compresses it when enabled and
+// removes the oldest segments.
+func (j *Journal) archive() error {
+	ext := filepath.Ext(j.path)
+	segment := strings.TrimSuffix(j.path, ext) + "-" + j.now().UTC().Format("20060102T150405.000000000") + ext
+	if err := os.Rename(j.path, segment); err != nil {
+		return err
+	}
+	if j.opts.Compress {
+		if err := compress(segment); err != nil {
+			return err
+		}
+	}
+	return j.removeOldSegments()
+}
+
+func (j *Journal) removeOldSegments() error {
+	if j.opts.MaxSegments <= 0 {
+		return nil
+	}
+	segments, err := Segments(j.path)
+	if err != nil {
+		return err
+	}
+	for len(segments) > j.opts.MaxSegments {
+		if err = os.Remove(segments[0]); err != nil {
+			return err
+		}
+		segments = segments[1:]
+	}
+	return nil
+}
+
+func compress(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err = io.Copy(zw, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Segments returns the rotated segments of the journal at path, from the oldest to the newest. The
+// current segment is not included.
+//
+// Parameters:
+//   - path: The path of the current segment of the journal.
+//
+// Returns:
+//   - []string: The paths of the rotated segments.
+//   - error: An error if the directory of the journal could not be read.
+func Segments(path string) ([]string, error) {
+	ext := filepath.Ext(path)
+	pattern := strings.TrimSuffix(path, ext) + "-*" + ext
+	plain, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid journal path %q: %w", path, err)
+	}
+	compressed, _ := filepath.Glob(pattern + ".gz")
+
+	// Without an extension, the plain pattern also matches the compressed segments.
+	segments := slices.DeleteFunc(plain, func(segment string) bool {
+		return strings.HasSuffix(segment, ".gz")
+	})
+	segments = append(segments, compressed...)
+	sortSegments(segments)
+	return segments, nil
+}
+
+// sortSegments sorts the segments by their rotation timestamp, ignoring the compression extension.
+func sortSegments(segments []string) {
+	sort.Slice(segments, func(i, k int) bool {
+		return strings.TrimSuffix(segments[i], ".gz") < strings.TrimSuffix(segments[k], ".gz")
+	})
+}
@@ -0,0 +1,212 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tech4works/errors"
+)
+
+func readAll(t *testing.T, path string) []Record {
+	t.Helper()
+
+	var records []Record
+	ReadJournal(path)(func(record Record, err error) bool {
+		if err != nil {
+			t.Fatalf("ReadJournal() error = %v", err)
+		}
+		records = append(records, record)
+		return true
+	})
+	return records
+}
+
+func TestJournal_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "errors.jsonl")
+	j, err := Open(path, Options{})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	j.Report(errors.WithHint(errors.New("disk full"), "free some space"))
+	j.Report(nil)
+	if err = j.Write(errors.Wrap(os.ErrNotExist, "reading config")); err != nil {
+		t.Fatalf("Journal.Write() error = %v", err)
+	}
+	if err = j.Close(); err != nil {
+		t.Fatalf("Journal.Close() error = %v", err)
+	}
+
+	records := readAll(t, path)
+	if len(records) != 2 {
+		t.Fatalf("ReadJournal() = %d records, want 2", len(records))
+	}
+	if records[0].Error.Message() != "disk full" || records[0].Error.Hints()[0] != "free some space" ||
+		records[0].Error.Func() != "TestJournal_Write" || records[0].Fingerprint == "" || records[0].Time.IsZero() {
+		t.Errorf("ReadJournal() first record = %+v", records[0])
+	}
+	if records[1].Error.Message() != "reading config: file does not exist" {
+		t.Errorf("ReadJournal() second record = %v", records[1].Error.Message())
+	}
+	if j.Write(errors.New("closed")) == nil {
+		t.Error("Journal.Write() should fail after Close")
+	}
+}
+
+func TestJournal_Rotation(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"Rotate by size", Options{MaxSize: 10}},
+		{"Rotate by size with compression", Options{MaxSize: 10, Compress: true}},
+		{"Rotate by age", Options{MaxAge: time.Minute}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "errors.jsonl")
+			j, err := Open(path, tt.opts)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+
+			clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			j.opened = clock
+			j.now = func() time.Time {
+				clock = clock.Add(time.Minute)
+				return clock
+			}
+			for i := 0; i < 3; i++ {
+				j.Report(errors.Newf("error %d", i))
+			}
+			_ = j.Close()
+
+			segments, _ := Segments(path)
+			if len(segments) != 2 {
+				t.Fatalf("Segments() = %v, want 2 rotated segments", segments)
+			}
+			if tt.opts.Compress && !strings.HasSuffix(segments[0], ".gz") {
+				t.Errorf("Segments() = %v, want compressed segments", segments)
+			}
+
+			records := readAll(t, path)
+			if len(records) != 3 {
+				t.Fatalf("ReadJournal() = %d records, want 3", len(records))
+			}
+			for i, record := range records {
+				if want := "error " + string(rune('0'+i)); record.Error.Message() != want {
+					t.Errorf("ReadJournal()[%d] = %v, want %v", i, record.Error.Message(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestJournal_MaxSegments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.jsonl")
+	j, err := Open(path, Options{MaxSize: 10, MaxSegments: 1})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		j.Report(errors.Newf("error %d", i))
+		if err = j.Rotate(); err != nil {
+			t.Fatalf("Journal.Rotate() error = %v", err)
+		}
+	}
+	_ = j.Close()
+
+	if segments, _ := Segments(path); len(segments) != 1 {
+		t.Errorf("Segments() = %v, want 1 segment", segments)
+	}
+}
+
+func TestJournal_RotateFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "errors.jsonl")
+	j, err := Open(path, Options{Compress: true})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer j.Close()
+	j.now = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	j.Report(errors.New("before"))
+	// A directory in place of the compressed segment makes the compression fail.
+	if err = os.Mkdir(filepath.Join(dir, "errors-20240101T000000.000000000.jsonl.gz"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err = j.Rotate(); err == nil {
+		t.Fatal("Journal.Rotate() error = nil, want an error")
+	}
+	if err = j.Write(errors.New("after")); err != nil {
+		t.Errorf("Journal.Write() after a failed rotation error = %v", err)
+	}
+}
+
+func TestJournal_ReopenKeepsAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.jsonl")
+	j, err := Open(path, Options{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	j.now = func() time.Time { return start }
+	j.Report(errors.New("first"))
+	_ = j.Close()
+
+	if j, err = Open(path, Options{MaxAge: time.Hour}); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer j.Close()
+	if !j.opened.Equal(start) {
+		t.Errorf("Open() opened = %v, want the time of the first record %v", j.opened, start)
+	}
+}
+
+func TestSegments_NoExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors")
+	j, err := Open(path, Options{Compress: true, MaxSegments: 2})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		j.Report(errors.Newf("error %d", i))
+		if err = j.Rotate(); err != nil {
+			t.Fatalf("Journal.Rotate() error = %v", err)
+		}
+	}
+	_ = j.Close()
+
+	segments, _ := Segments(path)
+	if len(segments) != 2 || !strings.HasSuffix(segments[0], ".gz") || !strings.HasSuffix(segments[1], ".gz") {
+		t.Fatalf("Segments() = %v, want 2 compressed segments", segments)
+	}
+	if records := readAll(t, path); len(records) != 2 {
+		t.Errorf("ReadJournal() = %d records, want 2", len(records))
+	}
+}
+
+func TestReadJournalInvalidLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.jsonl")
+	if err := os.WriteFile(path, []byte("not json\n{\"error\":{\"message\":\"ok\"}}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var errs, records int
+	ReadJournal(path)(func(record Record, err error) bool {
+		if err != nil {
+			errs++
+		} else {
+			records++
+		}
+		return true
+	})
+	if errs != 1 || records != 1 {
+		t.Errorf("ReadJournal() errors = %d, records = %d", errs, records)
+	}
+}
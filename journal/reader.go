@@ -0,0 +1,97 @@
+package journal
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ReadJournal returns an iterator over the records of the journal at path, reading the rotated
+// segments, compressed or not, from the oldest to the newest and then the current segment. Lines that
+// cannot be decoded are yielded as errors and the iteration continues; a failure to open or read a
+// segment is yielded as an error and ends the iteration. A missing current segment is not an error.
+//
+// The returned function has the signature of iter.Seq2[Record, error], so it can be used in a range
+// loop from Go 1.23, while it is called with the yield function on older versions.
+//
+// Parameters:
+//   - path: The path of the current segment of the journal.
+//
+// Returns:
+//   - func(yield func(Record, error) bool): The iterator over the records.
+//
+// Example:
+//
+//	journal.ReadJournal("/var/lib/app/errors.jsonl")(func(record journal.Record, err error) bool {
+//		if err != nil {
+//			log.Println(err)
+//			return true
+//		}
+//		fmt.Println(record.Time, record.Error.Cause())
+//		return true
+//	})
+func ReadJournal(path string) func(yield func(Record, error) bool) {
+	return func(yield func(Record, error) bool) {
+		segments, err := Segments(path)
+		if err != nil {
+			yield(Record{}, err)
+			return
+		}
+		if _, statErr := os.Stat(path); statErr == nil {
+			segments = append(segments, path)
+		}
+
+		for _, segment := range segments {
+			if !readSegment(segment, yield) {
+				return
+			}
+		}
+	}
+}
+
+// readSegment yields the records of a segment, returning false when the iteration must stop.
+func readSegment(path string, yield func(Record, error) bool) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		yield(Record{}, err)
+		return false
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		zr, gzipErr := gzip.NewReader(file)
+		if gzipErr != nil {
+			yield(Record{}, fmt.Errorf("%s: %w", path, gzipErr))
+			return false
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+
+		var record Record
+		if err = json.Unmarshal(line, &record); err != nil {
+			err = fmt.Errorf("%s:%d: %w", path, lineNumber, err)
+		}
+		if !yield(record, err) {
+			return false
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		yield(Record{}, fmt.Errorf("%s: %w", path, err))
+		return false
+	}
+	return true
+}
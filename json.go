@@ -0,0 +1,221 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// detailJSON is the JSON representation of an error of a chain. Errors that are not a *Detail only
+// carry their type, message and causes.
+type detailJSON struct {
+	Type        string            `json:"type,omitempty"`
+	Message     string            `json:"message"`
+	Code        string            `json:"code,omitempty"`
+	Kind        Kind              `json:"kind,omitempty"`
+	File        string            `json:"file,omitempty"`
+	Line        int               `json:"line,omitempty"`
+	Func        string            `json:"func,omitempty"`
+	Fields      Fields            `json:"fields,omitempty"`
+	Context     Fields            `json:"context_fields,omitempty"`
+	Hints       []string          `json:"hints,omitempty"`
+	DocsURL     string            `json:"docs_url,omitempty"`
+	Fingerprint string            `json:"fingerprint,omitempty"`
+	Stack       string            `json:"stack,omitempty"`
+	PCStack     *pcStackJSON      `json:"pc_stack,omitempty"`
+	Metadata    *Metadata         `json:"metadata,omitempty"`
+	Labels      map[string]string `json:"goroutine_labels,omitempty"`
+	Time        *time.Time        `json:"time,omitempty"`
+	Ops         []opJSON          `json:"ops,omitempty"`
+	Trace       []Frame           `json:"trace,omitempty"`
+	Cause       *detailJSON       `json:"cause,omitempty"`
+	Causes      []*detailJSON     `json:"causes,omitempty"`
+}
+
+// pcStackJSON is the JSON representation of a PCStack, which replaces the debug stack of the errors
+// captured with the StackPCs mode.
+type pcStackJSON struct {
+	BuildID string   `json:"build_id,omitempty"`
+	Anchor  string   `json:"anchor"`
+	PCs     []string `json:"pcs"`
+}
+
+// opJSON is the JSON representation of an OpStep, with the elapsed time as a duration string.
+type opJSON struct {
+	Name    string `json:"name"`
+	Elapsed string `json:"elapsed"`
+}
+
+// decodedError is an error that was not a *Detail when it was encoded.
+type decodedError struct {
+	typ     string
+	message string
+	cause   error
+}
+
+// decodedJoinError is an error that wrapped multiple errors when it was encoded.
+type decodedJoinError struct {
+	typ     string
+	message string
+	causes  []error
+}
+
+// MarshalJSON implements json.Marshaler. The error is encoded with its message, classification, origin,
+// fields, hints and debug stack, and the errors of its chain are nested under "cause", or "causes" for
+// errors that wrap multiple errors, such as the ones returned by the standard library errors.Join.
+func (e *Detail) MarshalJSON() ([]byte, error) {
+	return json.Marshal(encodeJSON(e))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding the representation written by MarshalJSON. Errors
+// of the chain that were not a *Detail are decoded as errors that keep their message and causes.
+func (e *Detail) UnmarshalJSON(data []byte) error {
+	var v detailJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*e = *v.detail()
+	return nil
+}
+
+func encodeJSON(err error) *detailJSON {
+	detail, ok := err.(*Detail)
+	if !ok {
+		v := &detailJSON{Type: typeOf(err), Message: err.Error()}
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			if cause := x.Unwrap(); cause != nil {
+				v.Cause = encodeJSON(cause)
+			}
+		case interface{ Unwrap() []error }:
+			for _, cause := range x.Unwrap() {
+				if cause != nil {
+					v.Causes = append(v.Causes, encodeJSON(cause))
+				}
+			}
+		}
+		return v
+	}
+
+	v := &detailJSON{
+		Message:     detail.message,
+		Code:        detail.code,
+		Kind:        detail.kind,
+		File:        detail.file,
+		Line:        detail.Line(),
+		Func:        detail.funcName,
+		Fields:      redactFields(detail.fields),
+		Context:     redactFields(detail.contextFields),
+		Hints:       detail.hints,
+		DocsURL:     detail.docsURL,
+		Fingerprint: detail.fingerprint,
+		Stack:       detail.stack,
+		Metadata:    detail.metadata,
+		Labels:      detail.labels,
+		Trace:       detail.trace,
+	}
+	if !detail.time.IsZero() {
+		v.Time = &detail.time
+	}
+	for _, step := range detail.ops {
+		v.Ops = append(v.Ops, opJSON{Name: step.Name, Elapsed: step.Elapsed.String()})
+	}
+	if stack, ok := detail.PCStack(); ok {
+		v.Stack = ""
+		v.PCStack = &pcStackJSON{BuildID: stack.BuildID, Anchor: formatPC(stack.Anchor)}
+		for _, pc := range stack.PCs {
+			v.PCStack.PCs = append(v.PCStack.PCs, formatPC(pc))
+		}
+	}
+	if detail.cause != nil {
+		v.Cause = encodeJSON(detail.cause)
+	}
+	return v
+}
+
+func (v *detailJSON) detail() *Detail {
+	detail := &Detail{
+		file:          v.File,
+		line:          strconv.Itoa(v.Line),
+		funcName:      v.Func,
+		message:       v.Message,
+		stack:         v.Stack,
+		code:          v.Code,
+		kind:          v.Kind,
+		fields:        v.Fields,
+		contextFields: v.Context,
+		hints:         v.Hints,
+		docsURL:       v.DocsURL,
+		fingerprint:   v.Fingerprint,
+		metadata:      v.Metadata,
+		labels:        v.Labels,
+		trace:         v.Trace,
+	}
+	if v.Time != nil {
+		detail.time = *v.Time
+	}
+	for _, step := range v.Ops {
+		elapsed, _ := time.ParseDuration(step.Elapsed)
+		detail.ops = append(detail.ops, OpStep{Name: step.Name, Elapsed: elapsed})
+	}
+	if v.PCStack != nil {
+		stack := PCStack{BuildID: v.PCStack.BuildID}
+		stack.Anchor, _ = parsePC(v.PCStack.Anchor)
+		for _, s := range v.PCStack.PCs {
+			if pc, ok := parsePC(s); ok {
+				stack.PCs = append(stack.PCs, pc)
+			}
+		}
+		detail.stack = stack.String()
+	}
+	if v.Cause != nil {
+		detail.cause = v.Cause.error()
+	}
+	return detail
+}
+
+func (v *detailJSON) error() error {
+	switch {
+	case v.Type == "":
+		return v.detail()
+	case len(v.Causes) > 0:
+		causes := make([]error, 0, len(v.Causes))
+		for _, cause := range v.Causes {
+			causes = append(causes, cause.error())
+		}
+		return &decodedJoinError{typ: v.Type, message: v.Message, causes: causes}
+	case v.Cause != nil:
+		return &decodedError{typ: v.Type, message: v.Message, cause: v.Cause.error()}
+	default:
+		return &decodedError{typ: v.Type, message: v.Message}
+	}
+}
+
+// typeOf returns the Go type of err, or the type it had when it was encoded for decoded errors.
+func typeOf(err error) string {
+	switch x := err.(type) {
+	case *decodedError:
+		return x.typ
+	case *decodedJoinError:
+		return x.typ
+	default:
+		return fmt.Sprintf("%T", err)
+	}
+}
+
+func (e *decodedError) Error() string {
+	return e.message
+}
+
+func (e *decodedError) Unwrap() error {
+	return e.cause
+}
+
+func (e *decodedJoinError) Error() string {
+	return e.message
+}
+
+func (e *decodedJoinError) Unwrap() []error {
+	return e.causes
+}
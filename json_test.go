@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+var errTestJSON = Define("TEST_JSON", NotFound, "user {id} not found", Hint("check the id"))
+
+func TestDetail_MarshalJSON(t *testing.T) {
+	err := Wrap(fmt.Errorf("query: %w", errors.Join(errTestJSON.New(42), io.EOF)), "loading profile")
+
+	bs, marshalErr := json.Marshal(err)
+	if marshalErr != nil {
+		t.Fatalf("json.Marshal() error = %v", marshalErr)
+	}
+
+	var v detailJSON
+	if unmarshalErr := json.Unmarshal(bs, &v); unmarshalErr != nil {
+		t.Fatalf("json.Unmarshal() error = %v", unmarshalErr)
+	}
+	if v.Message != "loading profile" || v.Func != "TestDetail_MarshalJSON" || v.Code != "TEST_JSON" || v.Stack == "" {
+		t.Errorf("Detail.MarshalJSON() = %s", bs)
+	}
+	if v.Cause == nil || v.Cause.Type != "*fmt.wrapError" || v.Cause.Cause == nil || len(v.Cause.Cause.Causes) != 2 {
+		t.Fatalf("Detail.MarshalJSON() cause = %s", bs)
+	}
+	if joined := v.Cause.Cause.Causes; joined[0].Code != "TEST_JSON" || joined[1].Message != "EOF" {
+		t.Errorf("Detail.MarshalJSON() causes = %s", bs)
+	}
+}
+
+func TestDetail_UnmarshalJSON(t *testing.T) {
+	original := Wrap(fmt.Errorf("query: %w", errors.Join(errTestJSON.New(42), io.EOF)), "loading profile")
+
+	bs, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var decoded Detail
+	if err = json.Unmarshal(bs, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	want := Details(original)
+	if decoded.Message() != want.Message() || decoded.Cause() != want.Cause() || decoded.Stack() != want.Stack() {
+		t.Errorf("Detail.UnmarshalJSON() = %v, want %v", decoded.Cause(), want.Cause())
+	}
+	if !errTestJSON.Is(&decoded) || len(decoded.Hints()) != 1 {
+		t.Errorf("Detail.UnmarshalJSON() should keep the chain, got code %v", decoded.Code())
+	}
+
+	bs2, _ := json.Marshal(&decoded)
+	if string(bs) != string(bs2) {
+		t.Errorf("Detail.UnmarshalJSON() should round trip:\n%s\n%s", bs, bs2)
+	}
+}
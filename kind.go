@@ -0,0 +1,89 @@
+package errors
+
+import "net/http"
+
+// Kind classifies an error into a broad, transport-independent category. The values follow the
+// canonical status codes used by gRPC so that they can be mapped to HTTP statuses, span statuses
+// or any other protocol without losing meaning.
+type Kind string
+
+const (
+	// Unknown is used when the error does not fit in any other kind or the kind was not informed.
+	Unknown Kind = "UNKNOWN"
+	// Canceled indicates that the operation was canceled, typically by the caller.
+	Canceled Kind = "CANCELED"
+	// InvalidArgument indicates that the client specified an invalid argument.
+	InvalidArgument Kind = "INVALID_ARGUMENT"
+	// DeadlineExceeded indicates that the deadline expired before the operation could complete.
+	DeadlineExceeded Kind = "DEADLINE_EXCEEDED"
+	// NotFound indicates that some requested entity was not found.
+	NotFound Kind = "NOT_FOUND"
+	// AlreadyExists indicates that the entity that a client attempted to create already exists.
+	AlreadyExists Kind = "ALREADY_EXISTS"
+	// PermissionDenied indicates that the caller does not have permission to execute the operation.
+	PermissionDenied Kind = "PERMISSION_DENIED"
+	// ResourceExhausted indicates that some resource has been exhausted, such as a quota.
+	ResourceExhausted Kind = "RESOURCE_EXHAUSTED"
+	// FailedPrecondition indicates that the system is not in a state required for the operation.
+	FailedPrecondition Kind = "FAILED_PRECONDITION"
+	// Aborted indicates that the operation was aborted, typically due to a concurrency issue.
+	Aborted Kind = "ABORTED"
+	// OutOfRange indicates that the operation was attempted past the valid range.
+	OutOfRange Kind = "OUT_OF_RANGE"
+	// Unimplemented indicates that the operation is not implemented or not supported.
+	Unimplemented Kind = "UNIMPLEMENTED"
+	// Internal indicates that some invariant expected by the system has been broken.
+	Internal Kind = "INTERNAL"
+	// Unavailable indicates that the service is currently unavailable.
+	Unavailable Kind = "UNAVAILABLE"
+	// DataLoss indicates unrecoverable data loss or corruption.
+	DataLoss Kind = "DATA_LOSS"
+	// Unauthenticated indicates that the request does not have valid authentication credentials.
+	Unauthenticated Kind = "UNAUTHENTICATED"
+)
+
+// String returns the textual representation of the Kind. An empty Kind is reported as Unknown.
+func (k Kind) String() string {
+	if k == "" {
+		return string(Unknown)
+	}
+	return string(k)
+}
+
+// HTTPStatus returns the HTTP status code that best represents the Kind.
+//
+// Returns:
+//   - int: The HTTP status code, http.StatusInternalServerError for Unknown or unrecognized kinds.
+//
+// Example:
+//
+//	fmt.Println(NotFound.HTTPStatus()) // 404
+//	fmt.Println(Kind("").HTTPStatus()) // 500
+func (k Kind) HTTPStatus() int {
+	switch k {
+	case Canceled:
+		return 499
+	case InvalidArgument, OutOfRange:
+		return http.StatusBadRequest
+	case DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case NotFound:
+		return http.StatusNotFound
+	case AlreadyExists, Aborted:
+		return http.StatusConflict
+	case PermissionDenied:
+		return http.StatusForbidden
+	case ResourceExhausted:
+		return http.StatusTooManyRequests
+	case FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case Unimplemented:
+		return http.StatusNotImplemented
+	case Unavailable:
+		return http.StatusServiceUnavailable
+	case Unauthenticated:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
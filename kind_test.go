@@ -0,0 +1,25 @@
+package errors
+
+import "testing"
+
+func TestKind_HTTPStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		kind Kind
+		want int
+	}{
+		{"Kind is empty", "", 500},
+		{"Kind is not found", NotFound, 404},
+		{"Kind is invalid argument", InvalidArgument, 400},
+		{"Kind is unauthenticated", Unauthenticated, 401},
+		{"Kind is unrecognized", Kind("OTHER"), 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.kind.HTTPStatus(); got != tt.want {
+				t.Errorf("Kind.HTTPStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package errors
+
+import (
+	"log/slog"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MetadataField selects a piece of build or runtime metadata stamped onto the errors. The fields can be
+// combined with the bitwise OR operator.
+type MetadataField uint32
+
+const (
+	// MetadataBuild stamps the path and version of the main module and the VCS revision and modified
+	// flag of the build, as read by runtime/debug.ReadBuildInfo.
+	MetadataBuild MetadataField = 1 << iota
+	// MetadataGoVersion stamps the Go version that built the binary.
+	MetadataGoVersion
+	// MetadataHost stamps the host name.
+	MetadataHost
+	// MetadataPID stamps the process ID.
+	MetadataPID
+	// MetadataGoroutine stamps the ID of the goroutine that created the error.
+	MetadataGoroutine
+
+	// MetadataNone disables the metadata, which is the default.
+	MetadataNone MetadataField = 0
+	// MetadataAll enables every metadata field.
+	MetadataAll = MetadataBuild | MetadataGoVersion | MetadataHost | MetadataPID | MetadataGoroutine
+)
+
+// Metadata is the build and runtime metadata stamped onto an error when it is created, which tells the
+// deployment, host and process that produced the error.
+type Metadata struct {
+	// Module is the path of the main module.
+	Module string `json:"module,omitempty"`
+	// Version is the version of the main module, "(devel)" when it was built from a working tree.
+	Version string `json:"version,omitempty"`
+	// Revision is the VCS revision of the build.
+	Revision string `json:"revision,omitempty"`
+	// Dirty reports whether the working tree had uncommitted changes when the binary was built.
+	Dirty bool `json:"dirty,omitempty"`
+	// GoVersion is the Go version that built the binary.
+	GoVersion string `json:"go_version,omitempty"`
+	// Hostname is the host name.
+	Hostname string `json:"hostname,omitempty"`
+	// PID is the process ID.
+	PID int `json:"pid,omitempty"`
+	// GoroutineID is the ID of the goroutine that created the error.
+	GoroutineID int64 `json:"goroutine_id,omitempty"`
+}
+
+// processMetadata holds the metadata that does not change while the process runs.
+var processMetadata = sync.OnceValue(func() Metadata {
+	m := Metadata{GoVersion: runtime.Version(), PID: os.Getpid()}
+	m.Hostname, _ = os.Hostname()
+	if info, ok := debug.ReadBuildInfo(); ok {
+		m.Module = info.Main.Path
+		m.Version = info.Main.Version
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				m.Revision = setting.Value
+			case "vcs.modified":
+				m.Dirty = setting.Value == "true"
+			}
+		}
+	}
+	return m
+})
+
+// SetMetadata sets the metadata fields stamped onto the errors created from now on, which are included
+// in their JSON and log/slog representations, which is the same as calling Configure with
+// ConfigMetadata. It is safe to be called concurrently, but it is usually called once during the program
+// initialization.
+//
+// Parameters:
+//   - fields: The combination of MetadataField values to be stamped, or MetadataNone.
+//
+// Example:
+//
+//	func main() {
+//		errors.SetMetadata(errors.MetadataBuild | errors.MetadataHost | errors.MetadataPID)
+//		// ...
+//	}
+func SetMetadata(fields MetadataField) {
+	Configure(ConfigMetadata(fields))
+}
+
+// Metadata returns the build and runtime metadata stamped onto the error when it was created.
+//
+// Returns:
+//   - Metadata: The metadata of the error.
+//   - bool: A boolean value indicating whether the error has metadata, which depends on SetMetadata.
+func (e *Detail) Metadata() (Metadata, bool) {
+	if e.metadata == nil {
+		return Metadata{}, false
+	}
+	return *e.metadata, true
+}
+
+// captureMetadata returns the metadata selected by SetMetadata for an error with the given stack, or nil
+// when no field is selected.
+func captureMetadata(stack string) *Metadata {
+	fields := loadConfig().Metadata
+	if fields == MetadataNone {
+		return nil
+	}
+
+	process := processMetadata()
+	var m Metadata
+	if fields&MetadataBuild != 0 {
+		m.Module, m.Version, m.Revision, m.Dirty = process.Module, process.Version, process.Revision, process.Dirty
+	}
+	if fields&MetadataGoVersion != 0 {
+		m.GoVersion = process.GoVersion
+	}
+	if fields&MetadataHost != 0 {
+		m.Hostname = process.Hostname
+	}
+	if fields&MetadataPID != 0 {
+		m.PID = process.PID
+	}
+	if fields&MetadataGoroutine != 0 {
+		if !strings.HasPrefix(stack, "goroutine ") {
+			buf := make([]byte, 64)
+			stack = string(buf[:runtime.Stack(buf, false)])
+		}
+		m.GoroutineID = parseGoroutineID(stack)
+	}
+	return &m
+}
+
+// parseGoroutineID parses the ID of the "goroutine N [status]:" header of a debug stack, returning 0
+// when there is no header.
+func parseGoroutineID(stack string) int64 {
+	rest, ok := strings.CutPrefix(stack, "goroutine ")
+	if !ok {
+		return 0
+	}
+	end := strings.IndexByte(rest, ' ')
+	if end < 0 {
+		return 0
+	}
+	id, _ := strconv.ParseInt(rest[:end], 10, 64)
+	return id
+}
+
+// logAttrs returns the non-empty metadata as log/slog attributes.
+func (m *Metadata) logAttrs() []any {
+	var attrs []any
+	add := func(key, value string) {
+		if value != "" {
+			attrs = append(attrs, slog.String(key, value))
+		}
+	}
+	add("module", m.Module)
+	add("version", m.Version)
+	add("revision", m.Revision)
+	if m.Dirty {
+		attrs = append(attrs, slog.Bool("dirty", true))
+	}
+	add("go_version", m.GoVersion)
+	add("hostname", m.Hostname)
+	if m.PID != 0 {
+		attrs = append(attrs, slog.Int("pid", m.PID))
+	}
+	if m.GoroutineID != 0 {
+		attrs = append(attrs, slog.Int64("goroutine_id", m.GoroutineID))
+	}
+	return attrs
+}
@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSetMetadata(t *testing.T) {
+	t.Cleanup(func() { SetMetadata(MetadataNone) })
+	hostname, _ := os.Hostname()
+
+	tests := []struct {
+		name   string
+		fields MetadataField
+		check  func(m Metadata) bool
+	}{
+		{"Go version", MetadataGoVersion, func(m Metadata) bool {
+			return m.GoVersion == runtime.Version() && m.PID == 0 && m.Hostname == ""
+		}},
+		{"Host and PID", MetadataHost | MetadataPID, func(m Metadata) bool {
+			return m.Hostname == hostname && m.PID == os.Getpid() && m.GoVersion == ""
+		}},
+		{"Goroutine", MetadataGoroutine, func(m Metadata) bool {
+			return m.GoroutineID > 0
+		}},
+		{"Build", MetadataBuild, func(m Metadata) bool {
+			return m.GoVersion == "" && m.Hostname == "" && m.PID == 0 && m.GoroutineID == 0
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetMetadata(tt.fields)
+			m, ok := Details(New("test error")).Metadata()
+			if !ok || !tt.check(m) {
+				t.Errorf("Detail.Metadata() = %+v, %v", m, ok)
+			}
+		})
+	}
+
+	SetMetadata(MetadataNone)
+	if m, ok := Details(New("test error")).Metadata(); ok {
+		t.Errorf("Detail.Metadata() = %+v, want no metadata", m)
+	}
+}
+
+func TestMetadataGoroutineWithPCs(t *testing.T) {
+	SetMetadata(MetadataGoroutine)
+	SetStackCapture(StackPCs)
+	t.Cleanup(func() {
+		SetMetadata(MetadataNone)
+		SetStackCapture(StackFull)
+	})
+
+	if m, _ := Details(New("test error")).Metadata(); m.GoroutineID <= 0 {
+		t.Errorf("Detail.Metadata() = %+v, want a goroutine ID", m)
+	}
+}
+
+func TestMetadataOutput(t *testing.T) {
+	SetMetadata(MetadataGoVersion | MetadataPID)
+	t.Cleanup(func() { SetMetadata(MetadataNone) })
+	detail := Details(New("test error"))
+
+	bs, err := json.Marshal(detail)
+	if err != nil {
+		t.Fatalf("Detail.MarshalJSON() error = %v", err)
+	}
+	if !strings.Contains(string(bs), `"metadata":{"go_version":"`+runtime.Version()+`"`) {
+		t.Errorf("Detail.MarshalJSON() = %s", bs)
+	}
+	var decoded Detail
+	if err = json.Unmarshal(bs, &decoded); err != nil {
+		t.Fatalf("Detail.UnmarshalJSON() error = %v", err)
+	}
+	if m, ok := decoded.Metadata(); !ok || m.PID != os.Getpid() {
+		t.Errorf("decoded Metadata() = %+v, %v", m, ok)
+	}
+
+	var sb strings.Builder
+	slog.New(slog.NewTextHandler(&sb, nil)).Error("failed", "err", detail)
+	if !strings.Contains(sb.String(), "err.metadata.go_version="+runtime.Version()) ||
+		!strings.Contains(sb.String(), "err.metadata.pid=") || strings.Contains(sb.String(), "hostname") {
+		t.Errorf("Detail.LogValue() = %s", sb.String())
+	}
+}
+
+func TestParseGoroutineID(t *testing.T) {
+	tests := []struct {
+		stack string
+		want  int64
+	}{
+		{"goroutine 17 [running]:\nmain.main()", 17},
+		{"pcs anchor=0x1", 0},
+		{"goroutine x [running]:", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseGoroutineID(tt.stack); got != tt.want {
+			t.Errorf("parseGoroutineID(%q) = %d, want %d", tt.stack, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package errors
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// OpStep is an operation that an error went through as it propagated, recorded by Op.
+type OpStep struct {
+	// Name is the name of the operation, e.g. "billing.charge".
+	Name string
+	// Elapsed is the time the operation took until it returned the error.
+	Elapsed time.Duration
+}
+
+// String returns the step in the "name(elapsed)" form used by OpTrail.
+func (s OpStep) String() string {
+	return s.Name + "(" + roundDuration(s.Elapsed).String() + ")"
+}
+
+// Time returns the time when the error was created. Errors created in this process carry the monotonic
+// clock reading, so the time elapsed since their creation can be measured with time.Since.
+//
+// Returns:
+//   - time.Time: The creation time, or the zero time when it is unknown.
+func (e *Detail) Time() time.Time {
+	return e.time
+}
+
+// Op starts the timing of an operation and returns the function that, when deferred, records the
+// operation name and its elapsed time into the error returned by the operation, if any. As the error
+// propagates through operations that use Op, they form a trail of operations, returned by Ops and
+// OpTrail, and the operations are added to its return trace, like Trace does. An error that is not a
+// *Detail is wrapped into one, which keeps the original error in its chain.
+//
+// Parameters:
+//   - name: The name of the operation.
+//   - errp: A pointer to the named error result of the operation.
+//
+// Returns:
+//   - func(): The function to be deferred, which records the operation into *errp.
+//
+// Example:
+//
+//	func Charge(ctx context.Context, order Order) (err error) {
+//		defer errors.Op("billing.charge", &err)()
+//		return callStripe(ctx, order)
+//	}
+//
+//	fmt.Println(errors.OpTrail(err)) // billing.charge(120ms) > stripe.call(118ms)
+func Op(name string, errp *error) func() {
+	start := time.Now()
+	return func() {
+		if errp == nil || *errp == nil {
+			return
+		}
+		step := OpStep{Name: name, Elapsed: time.Since(start)}
+
+		detail, ok := (*errp).(*Detail)
+		if ok {
+			clone := *detail
+			detail = &clone
+			detail.appendTrace(1)
+		} else {
+			detail = wrapTrace(1, *errp)
+		}
+		detail.ops = append(slices.Clip(detail.ops), step)
+		*errp = detail
+	}
+}
+
+// Ops returns the operations recorded by Op on err and on the errors wrapped by it, from the outermost
+// operation to the innermost one, where the error happened.
+//
+// Parameters:
+//   - err: The error to be inspected.
+//
+// Returns:
+//   - []OpStep: The operations of the error, or nil when there are none.
+func Ops(err error) []OpStep {
+	var chain []*Detail
+	walk(err, func(err error) bool {
+		if detail, ok := err.(*Detail); ok {
+			chain = append(chain, detail)
+		}
+		return false
+	})
+
+	var steps []OpStep
+	for _, detail := range chain {
+		for i := len(detail.ops) - 1; i >= 0; i-- {
+			steps = append(steps, detail.ops[i])
+		}
+	}
+	return steps
+}
+
+// OpTrail returns the operations recorded by Op on err as a trail, from the outermost operation to the
+// innermost one, e.g. "billing.charge(120ms) > stripe.call(118ms)".
+//
+// Parameters:
+//   - err: The error to be inspected.
+//
+// Returns:
+//   - string: The operation trail, or an empty string when there are no operations.
+func OpTrail(err error) string {
+	steps := Ops(err)
+	trail := make([]string, len(steps))
+	for i, step := range steps {
+		trail[i] = step.String()
+	}
+	return strings.Join(trail, " > ")
+}
+
+// roundDuration rounds d to three significant digits at most, so "120.43ms" is printed as "120ms".
+func roundDuration(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(time.Millisecond)
+	case d >= time.Microsecond:
+		return d.Round(time.Microsecond)
+	default:
+		return d
+	}
+}
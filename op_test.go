@@ -0,0 +1,104 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func stripeCall(fail error) (err error) {
+	defer Op("stripe.call", &err)()
+	time.Sleep(2 * time.Millisecond)
+	return fail
+}
+
+func billingCharge(fail error, wrapMsg string) (err error) {
+	defer Op("billing.charge", &err)()
+	err = stripeCall(fail)
+	if err != nil && wrapMsg != "" {
+		return Wrap(err, wrapMsg)
+	}
+	return err
+}
+
+func TestOp(t *testing.T) {
+	tests := []struct {
+		name string
+		fail error
+		wrap string
+	}{
+		{"Detailed error", New("card declined"), ""},
+		{"Wrapped detailed error", New("card declined"), "charging order"},
+		{"Standard error", io.ErrUnexpectedEOF, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := billingCharge(tt.fail, tt.wrap)
+
+			steps := Ops(err)
+			if len(steps) != 2 || steps[0].Name != "billing.charge" || steps[1].Name != "stripe.call" ||
+				steps[0].Elapsed < steps[1].Elapsed || steps[1].Elapsed < 2*time.Millisecond {
+				t.Fatalf("Ops() = %v", steps)
+			}
+			if trail := OpTrail(err); !strings.HasPrefix(trail, "billing.charge(") || !strings.Contains(trail, "ms) > stripe.call(") {
+				t.Errorf("OpTrail() = %v", trail)
+			}
+			if !strings.HasSuffix(Details(err).Message(), MessageOf(tt.fail)) {
+				t.Errorf("Op() message = %v, want the message of %v", Details(err).Message(), tt.fail)
+			}
+			if _, ok := tt.fail.(*Detail); !ok && !errors.Is(err, tt.fail) {
+				t.Errorf("Op() lost the original error %v", tt.fail)
+			}
+			if len(Ops(tt.fail)) != 0 {
+				t.Errorf("Op() changed the original error")
+			}
+		})
+	}
+
+	if err := billingCharge(nil, ""); err != nil {
+		t.Errorf("Op() = %v, want nil", err)
+	}
+}
+
+func TestOpStep_String(t *testing.T) {
+	tests := []struct {
+		elapsed time.Duration
+		want    string
+	}{
+		{120*time.Millisecond + 430*time.Microsecond, "op(120ms)"},
+		{1520 * time.Millisecond, "op(1.52s)"},
+		{15*time.Microsecond + 300, "op(15µs)"},
+		{300, "op(300ns)"},
+	}
+
+	for _, tt := range tests {
+		if got := (OpStep{Name: "op", Elapsed: tt.elapsed}).String(); got != tt.want {
+			t.Errorf("OpStep.String() = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func TestDetail_Time(t *testing.T) {
+	before := time.Now()
+	detail := Details(billingCharge(New("card declined"), ""))
+	if detail.Time().Before(before) || time.Since(detail.Time()) < 0 {
+		t.Errorf("Detail.Time() = %v, want after %v", detail.Time(), before)
+	}
+
+	bs, _ := json.Marshal(detail)
+	var decoded Detail
+	if err := json.Unmarshal(bs, &decoded); err != nil {
+		t.Fatalf("Detail.UnmarshalJSON() error = %v", err)
+	}
+	if !decoded.Time().Equal(detail.Time()) {
+		t.Errorf("decoded Time() = %v, want %v", decoded.Time(), detail.Time())
+	}
+	if !reflect.DeepEqual(Ops(&decoded), Ops(detail)) {
+		t.Errorf("decoded Ops() = %v, want %v", Ops(&decoded), Ops(detail))
+	}
+}
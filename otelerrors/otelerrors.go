@@ -0,0 +1,145 @@
+// Package otelerrors integrates the github.com/tech4works/errors package with OpenTelemetry tracing.
+// It records errors on spans following the OpenTelemetry semantic conventions for exceptions and stamps
+// the trace and span IDs of the active span onto the errors created with a context.
+package otelerrors
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tech4works/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys used when recording errors, as defined by the OpenTelemetry semantic conventions for
+// exceptions and errors, plus the ones specific to this package.
+const (
+	ExceptionTypeKey       = attribute.Key("exception.type")
+	ExceptionMessageKey    = attribute.Key("exception.message")
+	ExceptionStacktraceKey = attribute.Key("exception.stacktrace")
+	ErrorTypeKey           = attribute.Key("error.type")
+	ErrorCodeKey           = attribute.Key("error.code")
+	ErrorKindKey           = attribute.Key("error.kind")
+	ErrorFieldPrefix       = "error.field."
+)
+
+// Record records err on the span of ctx. See RecordSpan.
+//
+// Parameters:
+//   - ctx: The context that carries the span.
+//   - err: The error to be recorded.
+func Record(ctx context.Context, err error) {
+	RecordSpan(trace.SpanFromContext(ctx), err)
+}
+
+// RecordSpan records err on span as an "exception" event with the exception.type, exception.message and
+// exception.stacktrace attributes, and the origin and fields of the error. The status of the span is
+// set to Error, described by the error code, and the error.type attribute of the span is set to the code
+// of the error or, when it has none, to its kind. Nothing is done when err is nil or the span is not
+// recording.
+//
+// Parameters:
+//   - span: The span that receives the error.
+//   - err: The error to be recorded.
+//
+// Example:
+//
+//	ctx, span := tracer.Start(ctx, "charge")
+//	defer span.End()
+//
+//	if err := charge(ctx); err != nil {
+//		otelerrors.RecordSpan(span, err)
+//	}
+func RecordSpan(span trace.Span, err error) {
+	if err == nil || !span.IsRecording() {
+		return
+	}
+
+	detail := errors.Details(err)
+	attrs := []attribute.KeyValue{
+		ExceptionTypeKey.String(exceptionType(err, detail)),
+		ExceptionMessageKey.String(detail.Message()),
+		ExceptionStacktraceKey.String(detail.Stack()),
+		attribute.String("code.filepath", detail.File()),
+		attribute.Int("code.lineno", detail.Line()),
+		attribute.String("code.function", detail.Func()),
+	}
+	attrs = append(attrs, FieldAttributes(detail.Fields())...)
+	span.AddEvent("exception", trace.WithAttributes(attrs...))
+
+	errorType := detail.Code()
+	if errorType == "" {
+		errorType = detail.Kind().String()
+	}
+	span.SetAttributes(ErrorTypeKey.String(errorType), ErrorKindKey.String(detail.Kind().String()))
+	if detail.Code() != "" {
+		span.SetAttributes(ErrorCodeKey.String(detail.Code()))
+		span.SetStatus(codes.Error, detail.Code()+": "+detail.Message())
+	} else {
+		span.SetStatus(codes.Error, detail.Message())
+	}
+}
+
+// FieldAttributes converts the fields of an error to span attributes prefixed by ErrorFieldPrefix,
+// keeping the type of boolean, integer, float and string values and formatting any other value with
+// fmt.Sprint.
+//
+// Parameters:
+//   - fields: The fields of the error.
+//
+// Returns:
+//   - []attribute.KeyValue: The span attributes.
+func FieldAttributes(fields errors.Fields) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(fields))
+	for k, v := range fields {
+		key := attribute.Key(ErrorFieldPrefix + k)
+		switch value := v.(type) {
+		case bool:
+			attrs = append(attrs, key.Bool(value))
+		case int:
+			attrs = append(attrs, key.Int(value))
+		case int64:
+			attrs = append(attrs, key.Int64(value))
+		case float64:
+			attrs = append(attrs, key.Float64(value))
+		case string:
+			attrs = append(attrs, key.String(value))
+		default:
+			attrs = append(attrs, key.String(fmt.Sprint(value)))
+		}
+	}
+	return attrs
+}
+
+// TraceExtractor is an errors.ContextExtractor that records the "trace_id" and "span_id" fields of the
+// span of the context, so errors created with errors.NewCtx and errors.WrapCtx can be correlated with
+// their traces.
+func TraceExtractor(ctx context.Context) errors.Fields {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return nil
+	}
+	return errors.Fields{
+		"trace_id": spanContext.TraceID().String(),
+		"span_id":  spanContext.SpanID().String(),
+	}
+}
+
+// RegisterTraceExtractor registers TraceExtractor as an errors.ContextExtractor. It is meant to be
+// called once, during the program initialization, and returns the function that unregisters it.
+func RegisterTraceExtractor() func() {
+	return errors.RegisterContextExtractor(TraceExtractor)
+}
+
+func exceptionType(err error, detail *errors.Detail) string {
+	if detail.Code() != "" {
+		return detail.Code()
+	}
+	errors.Walk(err, func(next error, _ int) bool {
+		err = next
+		return false
+	})
+	return fmt.Sprintf("%T", err)
+}
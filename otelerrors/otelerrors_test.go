@@ -0,0 +1,100 @@
+package otelerrors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tech4works/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var errQuotaExceeded = errors.Define("OTEL_QUOTA_EXCEEDED", errors.ResourceExhausted, "tenant {tenant} exceeded its quota of {limit} requests")
+
+func newTracer() (*tracetest.InMemoryExporter, *sdktrace.TracerProvider) {
+	exporter := tracetest.NewInMemoryExporter()
+	return exporter, sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+}
+
+func attributesOf(kvs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	values := map[attribute.Key]attribute.Value{}
+	for _, kv := range kvs {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+func TestRecord(t *testing.T) {
+	exporter, provider := newTracer()
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "operation")
+	Record(ctx, errQuotaExceeded.New("acme", 100))
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || len(spans[0].Events) != 1 {
+		t.Fatalf("Record() should add one event, got %v", spans)
+	}
+
+	event := spans[0].Events[0]
+	attrs := attributesOf(event.Attributes)
+	if event.Name != "exception" {
+		t.Errorf("event name = %v, want exception", event.Name)
+	}
+	if attrs[ExceptionTypeKey].AsString() != "OTEL_QUOTA_EXCEEDED" {
+		t.Errorf("exception.type = %v", attrs[ExceptionTypeKey].AsString())
+	}
+	if attrs[ExceptionMessageKey].AsString() != "tenant acme exceeded its quota of 100 requests" {
+		t.Errorf("exception.message = %v", attrs[ExceptionMessageKey].AsString())
+	}
+	if attrs[ExceptionStacktraceKey].AsString() == "" {
+		t.Error("exception.stacktrace should not be empty")
+	}
+	if attrs["error.field.tenant"].Type() != attribute.STRING || attrs["error.field.tenant"].AsString() != "acme" {
+		t.Errorf("error.field.tenant = %v", attrs["error.field.tenant"].Emit())
+	}
+	if attrs["error.field.limit"].Type() != attribute.INT64 || attrs["error.field.limit"].AsInt64() != 100 {
+		t.Errorf("error.field.limit = %v", attrs["error.field.limit"].Emit())
+	}
+	if attrs["code.function"].AsString() != "TestRecord" {
+		t.Errorf("code.function = %v", attrs["code.function"].AsString())
+	}
+
+	if spans[0].Status.Code != codes.Error || spans[0].Status.Description != "OTEL_QUOTA_EXCEEDED: tenant acme exceeded its quota of 100 requests" {
+		t.Errorf("span status = %v", spans[0].Status)
+	}
+	spanAttrs := attributesOf(spans[0].Attributes)
+	if spanAttrs[ErrorTypeKey].AsString() != "OTEL_QUOTA_EXCEEDED" || spanAttrs[ErrorKindKey].AsString() != "RESOURCE_EXHAUSTED" {
+		t.Errorf("span attributes = %v", spans[0].Attributes)
+	}
+}
+
+func TestRecordNil(t *testing.T) {
+	exporter, provider := newTracer()
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "operation")
+	Record(ctx, nil)
+	span.End()
+
+	if spans := exporter.GetSpans(); len(spans[0].Events) != 0 || spans[0].Status.Code != codes.Unset {
+		t.Errorf("Record() should ignore nil errors, got %v", spans[0])
+	}
+}
+
+func TestTraceExtractor(t *testing.T) {
+	t.Cleanup(RegisterTraceExtractor())
+	_, provider := newTracer()
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "operation")
+	defer span.End()
+
+	fields := errors.Details(errors.NewCtx(ctx, "failed")).Fields()
+	if fields["trace_id"] != span.SpanContext().TraceID().String() || fields["span_id"] != span.SpanContext().SpanID().String() {
+		t.Errorf("NewCtx() fields = %v", fields)
+	}
+	if TraceExtractor(context.Background()) != nil {
+		t.Error("TraceExtractor() should return nil without a span")
+	}
+}
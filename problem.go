@@ -0,0 +1,57 @@
+package errors
+
+// Problem is the problem details representation of an error, as defined by RFC 9457 (formerly RFC
+// 7807), extended with the code and the fields of the error.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Params   Fields `json:"params,omitempty"`
+}
+
+// ToProblem converts an error to its problem details representation. When the error was created from a
+// Definition, the status, the title, the public message and the values of the template placeholders come
+// from it, while the other fields, such as the ones extracted from the context, are left out; otherwise
+// the problem is reported by the kind of the error, without exposing the error message. The
+// documentation URL of the error, when present, is used as the problem type.
+//
+// Parameters:
+//   - err: The error to be converted.
+//
+// Returns:
+//   - *Problem: The problem details of the error, or nil when err is nil.
+//
+// Example:
+//
+//	var ErrUserNotFound = Define("USER_NOT_FOUND", NotFound, "user {id} not found",
+//		PublicMessage("The user could not be found."))
+//
+//	problem := ToProblem(ErrUserNotFound.New(42))
+//	fmt.Println(problem.Status, problem.Title, problem.Detail) // 404 USER_NOT_FOUND The user could not be found.
+func ToProblem(err error) *Problem {
+	if err == nil {
+		return nil
+	}
+
+	detail := Details(err)
+	problem := &Problem{
+		Type:   "about:blank",
+		Title:  detail.Kind().String(),
+		Status: detail.Kind().HTTPStatus(),
+	}
+
+	if def, ok := lookup(detail.code); ok {
+		problem.Title = def.code
+		problem.Status = def.HTTPStatus()
+		problem.Detail = def.publicMessage
+		problem.Code = def.code
+		problem.Params = def.paramsOf(detail)
+	}
+	if detail.docsURL != "" {
+		problem.Type = detail.docsURL
+	}
+	return problem
+}
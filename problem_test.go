@@ -0,0 +1,41 @@
+package errors
+
+import "testing"
+
+var errTestProblem = Define("TEST_PROBLEM", NotFound, "user {id} not found",
+	PublicMessage("The user could not be found."), DocsURL("https://docs.example.com/errors/TEST_PROBLEM"))
+
+func TestToProblem(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want *Problem
+	}{
+		{"Error is nil", nil, nil},
+		{"Error without definition", New("database is down"), &Problem{Type: "about:blank", Title: "UNKNOWN", Status: 500}},
+		{"Error from definition", errTestProblem.New(42), &Problem{
+			Type:   "https://docs.example.com/errors/TEST_PROBLEM",
+			Title:  "TEST_PROBLEM",
+			Status: 404,
+			Detail: "The user could not be found.",
+			Code:   "TEST_PROBLEM",
+			Params: Fields{"id": 42},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToProblem(tt.err)
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("ToProblem() = %v, want %v", got, tt.want)
+			}
+			if got == nil {
+				return
+			}
+			if got.Type != tt.want.Type || got.Title != tt.want.Title || got.Status != tt.want.Status ||
+				got.Detail != tt.want.Detail || got.Code != tt.want.Code || len(got.Params) != len(tt.want.Params) {
+				t.Errorf("ToProblem() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package errors
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// RenderOption configures the report built by Render.
+type RenderOption func(c *renderConfig)
+
+type renderConfig struct {
+	colors       bool
+	linkFormat   string
+	sourceLines  int
+	sourceFrames int
+	runtime      bool
+	frames       []Frame
+	framesSet    bool
+	warnings     []string
+}
+
+const (
+	renderReset  = "\x1b[0m"
+	renderBold   = "\x1b[1m"
+	renderDim    = "\x1b[2m"
+	renderRed    = "\x1b[31m"
+	renderYellow = "\x1b[33m"
+	renderCyan   = "\x1b[36m"
+)
+
+// RenderColors enables or disables the ANSI colors of the report. By default, the colors are enabled
+// when the standard output is a terminal and the NO_COLOR environment variable is not set.
+func RenderColors(enabled bool) RenderOption {
+	return func(c *renderConfig) {
+		c.colors = enabled
+	}
+}
+
+// RenderHyperlinks sets the format of the OSC 8 hyperlinks added to the source locations of the report,
+// where "{path}", "{line}" and "{host}" are replaced by the absolute path of the file, the line number and
+// the host name, e.g. "vscode://file{path}:{line}". An empty format disables the hyperlinks. By default,
+// the hyperlinks are enabled along with the colors, using the "file://{host}{path}" format.
+func RenderHyperlinks(format string) RenderOption {
+	return func(c *renderConfig) {
+		c.linkFormat = format
+	}
+}
+
+// RenderSourceLines sets the number of source lines printed before and after the line of a frame, which
+// is 2 by default. A negative number disables the source snippets.
+func RenderSourceLines(n int) RenderOption {
+	return func(c *renderConfig) {
+		c.sourceLines = n
+	}
+}
+
+// RenderSourceFrames sets the number of application frames printed with their source, which is 3 by
+// default. The frames of the runtime and of the standard library are not counted.
+func RenderSourceFrames(n int) RenderOption {
+	return func(c *renderConfig) {
+		c.sourceFrames = n
+	}
+}
+
+// RenderRuntimeFrames sets whether the frames of the Go runtime are printed. When disabled, every run of
+// runtime frames is collapsed into a single line. They are printed by default.
+func RenderRuntimeFrames(show bool) RenderOption {
+	return func(c *renderConfig) {
+		c.runtime = show
+	}
+}
+
+// RenderFrames sets the stack frames printed by the report in place of the frames of the origin of the
+// error, e.g. the frames resolved from a PCStack captured by another program. Nil frames print no stack.
+func RenderFrames(frames []Frame) RenderOption {
+	return func(c *renderConfig) {
+		c.frames = frames
+		c.framesSet = true
+	}
+}
+
+// RenderWarning adds a warning to the report, printed after the attributes of the error, e.g. to tell
+// that its frames may be inaccurate.
+func RenderWarning(warning string) RenderOption {
+	return func(c *renderConfig) {
+		c.warnings = append(c.warnings, warning)
+	}
+}
+
+// Render builds a human-friendly report of err, meant to be printed by command line tools and during
+// local development. The report has the message chain of the error with the origin of every detailed
+// error, its code, fields, hints, documentation URL, operation trail and return trace, and the stack
+// frames where the error was created, with the source code around the line of the first application
+// frames, read from disk.
+//
+// Parameters:
+//   - err: The error to be rendered.
+//   - opts: Optional RenderOption values, such as RenderColors.
+//
+// Returns:
+//   - string: The report, or an empty string when err is nil.
+//
+// Example:
+//
+//	if err := run(); err != nil {
+//		fmt.Fprint(os.Stderr, errors.Render(err))
+//		os.Exit(1)
+//	}
+func Render(err error, opts ...RenderOption) string {
+	if err == nil {
+		return ""
+	}
+
+	config := renderConfig{sourceLines: 2, sourceFrames: 3, runtime: true}
+	config.colors = isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb"
+	if config.colors {
+		config.linkFormat = "file://{host}{path}"
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	r := renderer{config: config}
+	r.render(err)
+	return r.sb.String()
+}
+
+type renderer struct {
+	config renderConfig
+	sb     strings.Builder
+}
+
+func (r *renderer) paint(style, s string) string {
+	if !r.config.colors || s == "" {
+		return s
+	}
+	return style + s + renderReset
+}
+
+func (r *renderer) link(file string, line int, text string) string {
+	if r.config.linkFormat == "" || !strings.HasPrefix(file, "/") {
+		return text
+	}
+	host, _ := os.Hostname()
+	target := strings.NewReplacer(
+		"{path}", (&url.URL{Path: file}).EscapedPath(),
+		"{line}", strconv.Itoa(line),
+		"{host}", host,
+	).Replace(r.config.linkFormat)
+	return "\x1b]8;;" + target + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+func (r *renderer) render(err error) {
+	detail := Details(err)
+	origin := Origin(err)
+
+	r.sb.WriteString(r.paint(renderBold+renderRed, "error:") + " " + r.paint(renderBold, sanitize(MessageOf(err))) + "\n")
+	if _, ok := err.(*Detail); ok && detail.cause != nil {
+		r.chain(err, 1)
+	} else if ok && detail.file != "" {
+		r.field("at", sanitize(fmt.Sprintf("%s:%s %s", detail.file, detail.line, detail.funcName)))
+	}
+
+	if detail.code != "" {
+		r.field("code", sanitize(fmt.Sprintf("%s (%s)", detail.code, detail.Kind())))
+	}
+	if fields := detail.Fields(); len(fields) > 0 {
+		pairs := make([]string, 0, len(fields))
+		for _, key := range sortedKeys(fields) {
+			pairs = append(pairs, fmt.Sprintf("%s=%v", key, fields[key]))
+		}
+		r.field("fields", sanitize(strings.Join(pairs, " ")))
+	}
+	for _, hint := range detail.hints {
+		r.field("hint", r.paint(renderYellow, sanitize(hint)))
+	}
+	if detail.docsURL != "" {
+		r.field("docs", sanitize(detail.docsURL))
+	}
+	if trail := OpTrail(err); trail != "" {
+		r.field("ops", sanitize(trail))
+	}
+	if trace := ReturnTrace(err); len(trace) > 1 {
+		r.sb.WriteString("\n" + r.paint(renderCyan, "returned through:") + "\n")
+		for _, frame := range trace {
+			location := sanitize(fmt.Sprintf("%s:%d", frame.File, frame.Line))
+			r.sb.WriteString("  " + r.paint(renderBold, sanitize(frame.Function)) + " " +
+				r.paint(renderDim, r.link(frame.File, frame.Line, location)) + "\n")
+		}
+	}
+
+	for _, warning := range r.config.warnings {
+		r.field("warning", r.paint(renderYellow, sanitize(warning)))
+	}
+
+	frames := origin.Frames()
+	if r.config.framesSet {
+		frames = r.config.frames
+	}
+	r.frames(frames)
+}
+
+// chain writes every error of the chain of err as a tree, with the own message of the detailed errors
+// and their origin.
+func (r *renderer) chain(err error, depth int) {
+	Walk(err, func(err error, d int) bool {
+		indent := strings.Repeat("  ", depth+d)
+		if detail, ok := err.(*Detail); ok {
+			msg := detail.message
+			if msg == "" {
+				msg = "(no message)"
+			}
+			location := sanitize(fmt.Sprintf("%s:%s %s", detail.file, detail.line, detail.funcName))
+			r.sb.WriteString(indent + "└─ " + sanitize(msg) + "  " + r.paint(renderDim, location) + "\n")
+		} else {
+			r.sb.WriteString(indent + "└─ " + sanitize(MessageOf(err)) + "\n")
+		}
+		return false
+	})
+}
+
+func (r *renderer) field(label, value string) {
+	r.sb.WriteString(r.paint(renderCyan, fmt.Sprintf("%-7s", label+":")) + " " + value + "\n")
+}
+
+// frames writes the stack frames, with the source around the line of the first application frames.
+func (r *renderer) frames(frames []Frame) {
+	if len(frames) == 0 {
+		return
+	}
+	r.sb.WriteString("\n")
+
+	snippets := 0
+	for i := 0; i < len(frames); i++ {
+		frame := frames[i]
+		if !r.config.runtime && frame.IsRuntime() {
+			n := 1
+			for i+1 < len(frames) && frames[i+1].IsRuntime() {
+				i++
+				n++
+			}
+			label := "frames"
+			if n == 1 {
+				label = "frame"
+			}
+			r.sb.WriteString("  " + r.paint(renderDim, fmt.Sprintf("… %d runtime %s", n, label)) + "\n")
+			continue
+		}
+		location := sanitize(fmt.Sprintf("%s:%d", frame.File, frame.Line))
+		r.sb.WriteString("  at " + r.paint(renderBold, sanitize(frame.Function)) + " " +
+			r.paint(renderDim, r.link(frame.File, frame.Line, location)) + "\n")
+
+		if snippets < r.config.sourceFrames && r.config.sourceLines >= 0 && !frame.IsStandard() {
+			if r.snippet(frame) {
+				snippets++
+			}
+		}
+	}
+}
+
+// snippet writes the source lines around the line of the frame, highlighting it, and reports whether
+// the source file could be read.
+func (r *renderer) snippet(frame Frame) bool {
+	source, err := frame.Source(r.config.sourceLines)
+	if err != nil || len(source) == 0 {
+		return false
+	}
+
+	width := len(strconv.Itoa(source[len(source)-1].Number))
+	for _, line := range source {
+		text := sanitize(strings.ReplaceAll(line.Text, "\t", "    "))
+		number := fmt.Sprintf("%*d", width, line.Number)
+		if line.Current {
+			r.sb.WriteString("   " + r.paint(renderRed, "> "+number+" │ ") + r.paint(renderBold, text) + "\n")
+		} else {
+			r.sb.WriteString("     " + r.paint(renderDim, strings.TrimRight(number+" │ "+text, " ")) + "\n")
+		}
+	}
+	return true
+}
+
+// sanitize removes the C0 and C1 control characters of s, except for tabs, and replaces the line breaks
+// with spaces, so the text of an error, which may come from untrusted input, cannot move the cursor,
+// change the colors or inject hyperlinks when the report is printed to a terminal.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t':
+			return r
+		case r == '\n' || r == '\r':
+			return ' '
+		case r < 0x20 || r >= 0x7f && r < 0xa0:
+			return -1
+		default:
+			return r
+		}
+	}, s)
+}
+
+// isTerminal reports whether the file is a character device, such as a terminal.
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
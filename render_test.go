@@ -0,0 +1,139 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	def := Define("RENDER_NOT_FOUND", NotFound, "user {id} not found", Hint("check the user id"))
+	cause := def.New(42)
+	err := Wrap(cause, "loading profile")
+	line := Details(cause).Line()
+
+	tests := []struct {
+		name     string
+		err      error
+		opts     []RenderOption
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "Plain report",
+			err:  err,
+			opts: []RenderOption{RenderColors(false), RenderHyperlinks("")},
+			contains: []string{
+				"error: loading profile: user 42 not found\n",
+				"  └─ loading profile  ",
+				"    └─ user 42 not found  ",
+				"code:   RENDER_NOT_FOUND (NOT_FOUND)\n",
+				"fields: id=42\n",
+				"hint:   check the user id\n",
+				"  at github.com/tech4works/errors.TestRender ",
+				fmt.Sprintf("   > %d │     cause := def.New(42)\n", line),
+			},
+			excludes: []string{"\x1b"},
+		},
+		{
+			name:     "Colors and hyperlinks",
+			err:      err,
+			opts:     []RenderOption{RenderColors(true), RenderHyperlinks("vscode://file{path}:{line}")},
+			contains: []string{renderRed, "\x1b]8;;vscode://file/", fmt.Sprintf("render_test.go:%d\x1b\\", line)},
+		},
+		{
+			name:     "Without source",
+			err:      err,
+			opts:     []RenderOption{RenderColors(false), RenderSourceLines(-1)},
+			contains: []string{"  at github.com/tech4works/errors.TestRender "},
+			excludes: []string{" │ "},
+		},
+		{
+			name: "Control characters",
+			err:  Wrap(fmt.Errorf("bad \x1b]8;;https://evil.example\x1b\\input\x07\u009b2J"), "loading\r\nprofile"),
+			opts: []RenderOption{RenderColors(false), RenderHyperlinks("")},
+			contains: []string{
+				"error: loading profile: bad ]8;;https://evil.example\\input2J\n",
+				"    └─ bad ]8;;https://evil.example\\input2J\n",
+			},
+			excludes: []string{"\x1b", "\x07", "\u009b", "\r"},
+		},
+		{
+			name:     "Standard error",
+			err:      fmt.Errorf("failed: %w", io.ErrUnexpectedEOF),
+			opts:     []RenderOption{RenderColors(false)},
+			contains: []string{"error: failed: unexpected EOF\n"},
+			excludes: []string{"└─"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Render(tt.err, tt.opts...)
+			for _, s := range tt.contains {
+				if !strings.Contains(got, s) {
+					t.Errorf("Render() does not contain %q:\n%s", s, got)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(got, s) {
+					t.Errorf("Render() contains %q:\n%s", s, got)
+				}
+			}
+		})
+	}
+
+	if got := Render(nil); got != "" {
+		t.Errorf("Render(nil) = %q, want an empty string", got)
+	}
+}
+
+func TestRenderFrames(t *testing.T) {
+	frames := []Frame{
+		{Function: "github.com/org/app/service.(*Service).Get", File: "/app/service/service.go", Line: 10},
+		{Function: "main.main", File: "/app/main.go", Line: 5},
+		{Function: "runtime.main", File: "/go/src/runtime/proc.go", Line: 283},
+		{Function: "runtime.goexit", File: "/go/src/runtime/asm_amd64.s", Line: 1700},
+	}
+
+	tests := []struct {
+		name     string
+		opts     []RenderOption
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "Runtime frames",
+			contains: []string{"  at runtime.main /go/src/runtime/proc.go:283\n", "  at runtime.goexit "},
+		},
+		{
+			name:     "Collapsed runtime frames",
+			opts:     []RenderOption{RenderRuntimeFrames(false)},
+			contains: []string{"  at main.main /app/main.go:5\n  … 2 runtime frames\n"},
+			excludes: []string{"runtime.main"},
+		},
+		{
+			name:     "Warning",
+			opts:     []RenderOption{RenderWarning("frames may be wrong")},
+			contains: []string{"warning: frames may be wrong\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]RenderOption{RenderColors(false), RenderFrames(frames)}, tt.opts...)
+			got := Render(New("timeout"), opts...)
+			for _, s := range tt.contains {
+				if !strings.Contains(got, s) {
+					t.Errorf("Render() does not contain %q:\n%s", s, got)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(got, s) {
+					t.Errorf("Render() contains %q:\n%s", s, got)
+				}
+			}
+		})
+	}
+}
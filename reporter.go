@@ -0,0 +1,83 @@
+package errors
+
+import (
+	"slices"
+	"sync"
+)
+
+// Reporter receives the errors given to Report, e.g. to keep them in memory, write them to a file or
+// send them to an error tracker.
+type Reporter interface {
+	Report(err error)
+}
+
+// ReporterFunc is an adapter that allows the use of an ordinary function as a Reporter.
+type ReporterFunc func(err error)
+
+// Report calls f(err).
+func (f ReporterFunc) Report(err error) {
+	f(err)
+}
+
+// registration is a registered Reporter, identified by its address since reporters such as a ReporterFunc
+// cannot be compared.
+type registration struct {
+	reporter Reporter
+}
+
+var reporters struct {
+	sync.RWMutex
+	list []*registration
+}
+
+// RegisterReporter registers a Reporter that receives every error given to Report. Reporters are called
+// synchronously, in the order they were registered, so they should not block.
+//
+// Parameters:
+//   - reporter: The Reporter to be registered.
+//
+// Returns:
+//   - func(): The function that unregisters the Reporter, e.g. when a test ends. Calling it more than
+//     once has no effect.
+func RegisterReporter(reporter Reporter) func() {
+	reporters.Lock()
+	defer reporters.Unlock()
+
+	r := &registration{reporter: reporter}
+	reporters.list = append(reporters.list, r)
+	return func() {
+		reporters.Lock()
+		defer reporters.Unlock()
+
+		reporters.list = slices.DeleteFunc(reporters.list, func(other *registration) bool {
+			return other == r
+		})
+	}
+}
+
+// Report sends err to every registered Reporter. Nil errors are ignored.
+//
+// Parameters:
+//   - err: The error to be reported.
+//
+// Example:
+//
+//	RegisterReporter(ReporterFunc(func(err error) {
+//		log.Println("reported:", Details(err).Cause())
+//	}))
+//
+//	if err := process(); err != nil {
+//		Report(err)
+//	}
+func Report(err error) {
+	if err == nil {
+		return
+	}
+
+	reporters.RLock()
+	defer reporters.RUnlock()
+
+	for _, r := range reporters.list {
+		r.reporter.Report(err)
+	}
+}
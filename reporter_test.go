@@ -0,0 +1,42 @@
+package errors
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	var mu sync.Mutex
+	var reported []error
+	unregister := RegisterReporter(ReporterFunc(func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, err)
+	}))
+	t.Cleanup(unregister)
+
+	err := New("reported error")
+	Report(err)
+	Report(nil)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reported) != 1 || reported[0] != err {
+		t.Errorf("Report() reported = %v, want [%v]", reported, err)
+	}
+}
+
+func TestRegisterReporterUnregister(t *testing.T) {
+	var first, second int
+	unregister := RegisterReporter(ReporterFunc(func(error) { first++ }))
+	t.Cleanup(RegisterReporter(ReporterFunc(func(error) { second++ })))
+
+	Report(New("before"))
+	unregister()
+	unregister()
+	Report(New("after"))
+
+	if first != 1 || second != 2 {
+		t.Errorf("Report() called the reporters %d and %d times, want 1 and 2", first, second)
+	}
+}
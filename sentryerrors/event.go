@@ -0,0 +1,248 @@
+// Package sentryerrors encodes errors of the github.com/tech4works/errors package as events of the
+// Sentry protocol, and sends them to any server that speaks it, such as a self-hosted Sentry or a
+// compatible error tracker.
+package sentryerrors
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tech4works/errors"
+)
+
+// Event is an error event of the Sentry protocol.
+type Event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	Release     string            `json:"release,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Message     string            `json:"message,omitempty"`
+	Exception   *Exceptions       `json:"exception,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Extra       map[string]any    `json:"extra,omitempty"`
+	Fingerprint []string          `json:"fingerprint,omitempty"`
+	SDK         SDK               `json:"sdk"`
+}
+
+// Exceptions holds the exceptions of an Event, from the root cause to the outermost error.
+type Exceptions struct {
+	Values []Exception `json:"values"`
+}
+
+// Exception is a single error of the cause chain of an Event.
+type Exception struct {
+	Type       string      `json:"type"`
+	Value      string      `json:"value"`
+	Module     string      `json:"module,omitempty"`
+	Stacktrace *Stacktrace `json:"stacktrace,omitempty"`
+}
+
+// Stacktrace holds the frames of an Exception, from the outermost call to the innermost one.
+type Stacktrace struct {
+	Frames []Frame `json:"frames"`
+}
+
+// Frame is a single frame of a Stacktrace.
+type Frame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	AbsPath  string `json:"abs_path,omitempty"`
+	Lineno   int    `json:"lineno,omitempty"`
+	InApp    bool   `json:"in_app"`
+}
+
+// SDK identifies the library that produced an Event.
+type SDK struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Options customizes the events built by NewEvent.
+type Options struct {
+	// Release is the version of the application, e.g. a git revision.
+	Release string
+	// Environment is the environment of the application, e.g. "production".
+	Environment string
+	// ServerName is the name of the host that produced the event.
+	ServerName string
+	// InAppPrefixes are the package path prefixes of the application code. Frames of packages matching
+	// one of them are marked as in_app. When empty, the path of the main module is used.
+	InAppPrefixes []string
+}
+
+// NewEvent builds a Sentry Event from err. Every error of the cause chain becomes an Exception, from the
+// root cause to err itself, with the structured frames of the debug stack of the *errors.Detail values.
+// Errors that wrap multiple errors, such as the ones returned by the standard library errors.Join, add
+// the exceptions of every wrapped error. The code and the kind of the error are reported as tags, while
+// its fields, which usually have too many distinct values to be searched as tags, are reported as extra
+// data. errors.Fingerprint is used as the fingerprint of the event, so the same bug is grouped together
+// across deployments.
+//
+// Parameters:
+//   - err: The error to be encoded.
+//   - opts: The Options of the event.
+//
+// Returns:
+//   - *Event: The Sentry event, or nil when err is nil.
+func NewEvent(err error, opts Options) *Event {
+	if err == nil {
+		return nil
+	}
+
+	inAppPrefixes := opts.InAppPrefixes
+	if len(inAppPrefixes) == 0 {
+		inAppPrefixes = mainModule()
+	}
+
+	detail := errors.Details(err)
+	event := &Event{
+		EventID:     newEventID(),
+		Timestamp:   time.Now().UTC(),
+		Platform:    "go",
+		Level:       "error",
+		Release:     opts.Release,
+		Environment: opts.Environment,
+		ServerName:  opts.ServerName,
+		Exception:   &Exceptions{Values: exceptions(err, inAppPrefixes)},
+		Tags:        map[string]string{},
+		Fingerprint: []string{errors.Fingerprint(err)},
+		SDK:         SDK{Name: "tech4works.errors", Version: sdkVersion()},
+	}
+
+	if detail.Code() != "" {
+		event.Tags["code"] = detail.Code()
+	}
+	event.Tags["kind"] = detail.Kind().String()
+
+	extra := map[string]any{}
+	if fields := detail.Fields(); len(fields) > 0 {
+		extra["fields"] = fields
+	}
+	if hints := detail.Hints(); len(hints) > 0 {
+		extra["hints"] = hints
+	}
+	if docsURL := detail.DocsURL(); docsURL != "" {
+		extra["docs_url"] = docsURL
+	}
+	if len(extra) > 0 {
+		event.Extra = extra
+	}
+	return event
+}
+
+// exceptions converts the tree of err to exceptions, ordered from the root causes to err.
+func exceptions(err error, inAppPrefixes []string) []Exception {
+	var values []Exception
+	var visit func(err error)
+	visit = func(err error) {
+		values = append(values, exception(err, inAppPrefixes))
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			if cause := x.Unwrap(); cause != nil {
+				visit(cause)
+			}
+		case interface{ Unwrap() []error }:
+			for _, cause := range x.Unwrap() {
+				if cause != nil {
+					visit(cause)
+				}
+			}
+		}
+	}
+	visit(err)
+	slices.Reverse(values)
+	return values
+}
+
+// exception converts a single error of the tree to an Exception, with the message of the errors that are
+// not a *errors.Detail stripped of the origin and stack of a Detail they embed.
+func exception(err error, inAppPrefixes []string) Exception {
+	detail, ok := err.(*errors.Detail)
+	if !ok {
+		return Exception{Type: fmt.Sprintf("%T", err), Value: errors.MessageOf(err)}
+	}
+
+	value := Exception{Type: detail.Code(), Value: detail.Message()}
+	if value.Type == "" {
+		value.Type = detail.Kind().String()
+	}
+	frames := detail.Frames()
+	if len(frames) > 0 {
+		value.Module = frames[0].Package()
+		value.Stacktrace = &Stacktrace{Frames: stacktraceFrames(frames, inAppPrefixes)}
+	}
+	return value
+}
+
+func stacktraceFrames(frames []errors.Frame, inAppPrefixes []string) []Frame {
+	result := make([]Frame, 0, len(frames))
+	for i := len(frames) - 1; i >= 0; i-- {
+		frame := frames[i]
+		module := frame.Package()
+		result = append(result, Frame{
+			Function: frame.ShortFunction(),
+			Module:   module,
+			Filename: shortFilename(frame.File),
+			AbsPath:  frame.File,
+			Lineno:   frame.Line,
+			InApp:    isInApp(module, inAppPrefixes),
+		})
+	}
+	return result
+}
+
+func isInApp(module string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if module == prefix || strings.HasPrefix(module, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// shortFilename returns the last two elements of path, e.g. "users/service.go".
+func shortFilename(path string) string {
+	slash := strings.LastIndexByte(path, '/')
+	if slash <= 0 {
+		return path
+	}
+	if parent := strings.LastIndexByte(path[:slash], '/'); parent >= 0 {
+		return path[parent+1:]
+	}
+	return path
+}
+
+func mainModule() []string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Path == "" {
+		return []string{"main"}
+	}
+	return []string{"main", info.Main.Path}
+}
+
+func sdkVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if ok {
+		for _, dep := range info.Deps {
+			if dep.Path == "github.com/tech4works/errors" {
+				return dep.Version
+			}
+		}
+	}
+	return "devel"
+}
+
+func newEventID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
@@ -0,0 +1,95 @@
+package sentryerrors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tech4works/errors"
+)
+
+var errPaymentDeclined = errors.Define("SENTRY_PAYMENT_DECLINED", errors.FailedPrecondition, "payment {id} declined",
+	errors.Hint("ask the customer for another card"),
+	errors.DocsURL("https://docs.example.com/errors/SENTRY_PAYMENT_DECLINED"))
+
+func TestNewEvent(t *testing.T) {
+	err := errors.Wrap(errPaymentDeclined.New(42), "charging order")
+
+	event := NewEvent(err, Options{Release: "v1.2.3", InAppPrefixes: []string{"github.com/tech4works/errors/sentryerrors"}})
+	if len(event.EventID) != 32 || event.Platform != "go" || event.Level != "error" || event.Release != "v1.2.3" {
+		t.Errorf("NewEvent() = %+v", event)
+	}
+	if len(event.Tags) != 2 || event.Tags["code"] != "SENTRY_PAYMENT_DECLINED" || event.Tags["kind"] != "FAILED_PRECONDITION" {
+		t.Errorf("NewEvent() tags = %v", event.Tags)
+	}
+	if fields, _ := event.Extra["fields"].(errors.Fields); fields["id"] != 42 {
+		t.Errorf("NewEvent() extra = %v", event.Extra)
+	}
+	if hints, _ := event.Extra["hints"].([]string); len(hints) != 1 || hints[0] != "ask the customer for another card" ||
+		event.Extra["docs_url"] != "https://docs.example.com/errors/SENTRY_PAYMENT_DECLINED" {
+		t.Errorf("NewEvent() extra = %v", event.Extra)
+	}
+	if len(event.Fingerprint) != 1 || event.Fingerprint[0] != errors.Fingerprint(err) {
+		t.Errorf("NewEvent() fingerprint = %v", event.Fingerprint)
+	}
+
+	values := event.Exception.Values
+	if len(values) != 2 {
+		t.Fatalf("NewEvent() exceptions = %+v", values)
+	}
+	if values[0].Value != "payment 42 declined" || values[1].Value != "charging order: payment 42 declined" {
+		t.Errorf("NewEvent() exceptions should go from the root cause to the error, got %+v", values)
+	}
+
+	frames := values[1].Stacktrace.Frames
+	last := frames[len(frames)-1]
+	if last.Function != "TestNewEvent" || !last.InApp || last.Filename != "sentryerrors/event_test.go" {
+		t.Errorf("NewEvent() last frame = %+v", last)
+	}
+	if first := frames[0]; first.InApp {
+		t.Errorf("NewEvent() frame %+v should not be in app", first)
+	}
+}
+
+func TestNewEventStandardError(t *testing.T) {
+	event := NewEvent(stderrors.New("boom"), Options{})
+	values := event.Exception.Values
+	if len(values) != 1 || values[0].Value != "boom" || values[0].Stacktrace != nil {
+		t.Errorf("NewEvent() exceptions = %+v", values)
+	}
+	if len(event.Fingerprint) != 1 || event.Fingerprint[0] == "" {
+		t.Errorf("NewEvent() fingerprint = %v", event.Fingerprint)
+	}
+}
+
+func TestNewEventWrappers(t *testing.T) {
+	err := stderrors.Join(
+		fmt.Errorf("charging order: %w", errPaymentDeclined.New(42)),
+		stderrors.New("cache unavailable"),
+	)
+
+	values := NewEvent(err, Options{}).Exception.Values
+	var got []string
+	for _, value := range values {
+		got = append(got, value.Value)
+		if strings.Contains(value.Value, "[STACK]") || strings.Contains(value.Value, "[CAUSE]") {
+			t.Errorf("NewEvent() exception value = %q", value.Value)
+		}
+	}
+	want := []string{
+		"cache unavailable",
+		"payment 42 declined",
+		"charging order: payment 42 declined",
+		"charging order: payment 42 declined cache unavailable",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("NewEvent() exceptions = %q, want %q", got, want)
+	}
+}
+
+func TestNewEventNil(t *testing.T) {
+	if NewEvent(nil, Options{}) != nil {
+		t.Error("NewEvent() should return nil for nil errors")
+	}
+}
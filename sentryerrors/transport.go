@@ -0,0 +1,179 @@
+package sentryerrors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DSN is a parsed Sentry DSN, in the form "https://<public key>@<host>/<project id>".
+type DSN struct {
+	raw       string
+	publicKey string
+	scheme    string
+	host      string
+	path      string
+	projectID string
+}
+
+// ParseDSN parses a Sentry DSN.
+//
+// Parameters:
+//   - raw: The DSN, e.g. "https://public@sentry.example.com/42".
+//
+// Returns:
+//   - *DSN: The parsed DSN.
+//   - error: An error if the DSN is malformed.
+func ParseDSN(raw string) (*DSN, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DSN: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid DSN: unsupported scheme %q", u.Scheme)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("invalid DSN: missing public key")
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	slash := strings.LastIndexByte(path, '/')
+	projectID := path[slash+1:]
+	if projectID == "" {
+		return nil, fmt.Errorf("invalid DSN: missing project id")
+	}
+
+	return &DSN{
+		raw:       raw,
+		publicKey: u.User.Username(),
+		scheme:    u.Scheme,
+		host:      u.Host,
+		path:      path[:slash+1],
+		projectID: projectID,
+	}, nil
+}
+
+// String returns the DSN as it was parsed.
+func (d *DSN) String() string {
+	return d.raw
+}
+
+// EnvelopeURL returns the URL of the envelope endpoint of the project.
+func (d *DSN) EnvelopeURL() string {
+	return fmt.Sprintf("%s://%s%sapi/%s/envelope/", d.scheme, d.host, d.path, d.projectID)
+}
+
+// AuthHeader returns the value of the X-Sentry-Auth header for the DSN.
+func (d *DSN) AuthHeader() string {
+	return fmt.Sprintf("Sentry sentry_version=7, sentry_key=%s, sentry_client=%s/%s", d.publicKey, "tech4works.errors",
+		sdkVersion())
+}
+
+// WriteEnvelope writes event as a Sentry envelope with a single event item.
+//
+// Parameters:
+//   - w: The writer where the envelope is written.
+//   - dsn: The DSN of the project, written in the envelope header. It may be nil.
+//   - event: The event to be written.
+//
+// Returns:
+//   - error: An error if the envelope could not be encoded or written.
+func WriteEnvelope(w io.Writer, dsn *DSN, event *Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	header := map[string]any{
+		"event_id": event.EventID,
+		"sent_at":  time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if dsn != nil {
+		header["dsn"] = dsn.String()
+	}
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+	itemHeaderJSON, err := json.Marshal(map[string]any{"type": "event", "length": len(payload)})
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(headerJSON)
+	buf.WriteByte('\n')
+	buf.Write(itemHeaderJSON)
+	buf.WriteByte('\n')
+	buf.Write(payload)
+	buf.WriteByte('\n')
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// Transport sends events to the envelope endpoint of a Sentry DSN.
+type Transport struct {
+	dsn    *DSN
+	client *http.Client
+}
+
+// NewTransport creates a Transport for the given DSN. The client is used to send the requests, so
+// timeouts, proxies and TLS settings can be configured; when nil, http.DefaultClient is used.
+//
+// Parameters:
+//   - dsn: The Sentry DSN.
+//   - client: The HTTP client used to send the events.
+//
+// Returns:
+//   - *Transport: The transport.
+//   - error: An error if the DSN is malformed.
+func NewTransport(dsn string, client *http.Client) (*Transport, error) {
+	parsed, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Transport{dsn: parsed, client: client}, nil
+}
+
+// Send sends event to the server as an envelope.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - event: The event to be sent.
+//
+// Returns:
+//   - error: An error if the request failed or the server did not accept the event.
+func (t *Transport) Send(ctx context.Context, event *Event) error {
+	var body bytes.Buffer
+	if err := WriteEnvelope(&body, t.dsn, event); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.dsn.EnvelopeURL(), &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", t.dsn.AuthHeader())
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("sentry server responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package sentryerrors
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tech4works/errors"
+)
+
+func TestParseDSN(t *testing.T) {
+	tests := []struct {
+		name    string
+		dsn     string
+		wantURL string
+		wantErr bool
+	}{
+		{"Valid DSN", "https://public@sentry.example.com/42", "https://sentry.example.com/api/42/envelope/", false},
+		{"DSN with path", "http://public@example.com/sentry/7", "http://example.com/sentry/api/7/envelope/", false},
+		{"Missing key", "https://sentry.example.com/42", "", true},
+		{"Missing project", "https://public@sentry.example.com/", "", true},
+		{"Invalid scheme", "ftp://public@sentry.example.com/42", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dsn, err := ParseDSN(tt.dsn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDSN() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && dsn.EnvelopeURL() != tt.wantURL {
+				t.Errorf("DSN.EnvelopeURL() = %v, want %v", dsn.EnvelopeURL(), tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestWriteEnvelope(t *testing.T) {
+	event := NewEvent(errors.New("boom"), Options{})
+
+	var buf bytes.Buffer
+	if err := WriteEnvelope(&buf, nil, event); err != nil {
+		t.Fatalf("WriteEnvelope() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("WriteEnvelope() should write 3 lines, got %q", buf.String())
+	}
+
+	var itemHeader struct {
+		Type   string `json:"type"`
+		Length int    `json:"length"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &itemHeader); err != nil || itemHeader.Type != "event" ||
+		itemHeader.Length != len(lines[2]) {
+		t.Errorf("WriteEnvelope() item header = %v, error = %v", lines[1], err)
+	}
+}
+
+func TestTransport_Send(t *testing.T) {
+	var gotAuth, gotPath string
+	var gotEvent Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("X-Sentry-Auth")
+		gotPath = r.URL.Path
+
+		scanner := bufio.NewScanner(r.Body)
+		scanner.Buffer(nil, 1<<20)
+		for i := 0; scanner.Scan(); i++ {
+			if i == 2 {
+				_ = json.Unmarshal(scanner.Bytes(), &gotEvent)
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dsn := strings.Replace(server.URL, "://", "://public@", 1) + "/42"
+	transport, err := NewTransport(dsn, server.Client())
+	if err != nil {
+		t.Fatalf("NewTransport() error = %v", err)
+	}
+
+	event := NewEvent(errors.New("boom"), Options{})
+	if err = transport.Send(context.Background(), event); err != nil {
+		t.Fatalf("Transport.Send() error = %v", err)
+	}
+	if gotPath != "/api/42/envelope/" || !strings.Contains(gotAuth, "sentry_key=public") {
+		t.Errorf("Transport.Send() path = %v, auth = %v", gotPath, gotAuth)
+	}
+	if gotEvent.EventID != event.EventID || gotEvent.Exception.Values[0].Value != "boom" {
+		t.Errorf("Transport.Send() event = %+v", gotEvent)
+	}
+}
+
+func TestTransport_SendRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	transport, _ := NewTransport(strings.Replace(server.URL, "://", "://public@", 1)+"/42", nil)
+	if err := transport.Send(context.Background(), NewEvent(errors.New("boom"), Options{})); err == nil {
+		t.Error("Transport.Send() should fail when the server rejects the event")
+	}
+}
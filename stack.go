@@ -0,0 +1,189 @@
+package errors
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Frame is a single frame of a debug stack, as printed by runtime/debug.Stack.
+type Frame struct {
+	// Function is the fully qualified name of the function, e.g. "github.com/org/app/pkg.(*Type).Method".
+	Function string `json:"function"`
+	// File is the absolute path of the source file.
+	File string `json:"file"`
+	// Line is the line number in the source file.
+	Line int `json:"line"`
+}
+
+// Package returns the import path of the package of the frame function, e.g. "github.com/org/app/pkg".
+func (f Frame) Package() string {
+	slash := strings.LastIndexByte(f.Function, '/')
+	dot := strings.IndexByte(f.Function[slash+1:], '.')
+	if dot < 0 {
+		return f.Function
+	}
+	return f.Function[:slash+1+dot]
+}
+
+// ShortFunction returns the name of the frame function without its package, e.g. "(*Type).Method".
+func (f Frame) ShortFunction() string {
+	return strings.TrimPrefix(strings.TrimPrefix(f.Function, f.Package()), ".")
+}
+
+// IsRuntime reports whether the frame belongs to the Go runtime or to the testing package.
+func (f Frame) IsRuntime() bool {
+	pkg := f.Package()
+	return pkg == "runtime" || strings.HasPrefix(pkg, "runtime/") || pkg == "testing"
+}
+
+// IsStandard reports whether the frame belongs to the standard library, including the runtime, whose
+// import paths have no dot in their first element, as opposed to the application and its dependencies.
+func (f Frame) IsStandard() bool {
+	pkg := f.Package()
+	first, _, _ := strings.Cut(pkg, "/")
+	return pkg != "main" && !strings.Contains(first, ".")
+}
+
+// SourceLine is a line of the source file of a Frame.
+type SourceLine struct {
+	// Number is the line number, starting at 1.
+	Number int `json:"number"`
+	// Text is the content of the line, without the line break.
+	Text string `json:"text"`
+	// Current reports whether this is the line of the frame.
+	Current bool `json:"current,omitempty"`
+}
+
+// Source reads the lines of the frame source file around the line of the frame, which is only possible
+// where the source code is available, such as during local development.
+//
+// Parameters:
+//   - context: The number of lines read before and after the line of the frame.
+//
+// Returns:
+//   - []SourceLine: The lines of the source file, in order.
+//   - error: An error if the source file could not be read.
+//
+// Example:
+//
+//	for _, line := range frame.Source(2) {
+//		fmt.Printf("%4d %s\n", line.Number, line.Text)
+//	}
+func (f Frame) Source(context int) ([]SourceLine, error) {
+	file, err := os.Open(f.File)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	first, last := f.Line-context, f.Line+context
+	var lines []SourceLine
+	scanner := bufio.NewScanner(file)
+	for n := 1; n <= last && scanner.Scan(); n++ {
+		if n >= first {
+			lines = append(lines, SourceLine{Number: n, Text: scanner.Text(), Current: n == f.Line})
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// ParseStack parses a debug stack, as returned by runtime/debug.Stack or Detail.Stack, into its frames,
+// ordered from the innermost call to the outermost one. The goroutine header, the function arguments,
+// the program counter offsets and the "created by" frame are discarded.
+//
+// Parameters:
+//   - stack: The debug stack to be parsed.
+//
+// Returns:
+//   - []Frame: The frames of the stack.
+//
+// Example:
+//
+//	frames := ParseStack(string(debug.Stack()))
+//	fmt.Println(frames[0].Function) // runtime/debug.Stack
+func ParseStack(stack string) []Frame {
+	var frames []Frame
+	lines := strings.Split(strings.ReplaceAll(stack, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines)-1; i++ {
+		function := strings.TrimSpace(lines[i])
+		location := lines[i+1]
+		if function == "" || strings.HasPrefix(function, "goroutine ") || !strings.HasPrefix(location, "\t") {
+			continue
+		}
+		i++
+		if strings.HasPrefix(function, "created by ") {
+			continue
+		}
+
+		file, line := parseLocation(strings.TrimSpace(location))
+		frames = append(frames, Frame{Function: trimArguments(function), File: file, Line: line})
+	}
+	return frames
+}
+
+// Frames returns the frames of the debug stack of the error, starting at the frame where the error was
+// created, so the frames of runtime/debug.Stack and of the constructors of this package are skipped.
+// When the origin frame cannot be found, every frame after runtime/debug.Stack is returned. The program
+// counters of errors created in this process with the StackPCs mode are resolved by the runtime, while
+// decoded errors carrying a PCStack have no frames until they are symbolized against their binary.
+//
+// Returns:
+//   - []Frame: The frames of the error stack.
+func (e *Detail) Frames() []Frame {
+	if e.pcs != nil {
+		return pcFrames(e.pcs)
+	}
+	frames := ParseStack(e.stack)
+	for i, frame := range frames {
+		if strconv.Itoa(frame.Line) == e.line && matchesFile(frame.File, e.file) {
+			return frames[i:]
+		}
+	}
+	if len(frames) > 0 && frames[0].Function == "runtime/debug.Stack" {
+		return frames[1:]
+	}
+	return frames
+}
+
+func parseLocation(location string) (string, int) {
+	if i := strings.LastIndex(location, " +0x"); i >= 0 {
+		location = location[:i]
+	}
+	i := strings.LastIndexByte(location, ':')
+	if i < 0 {
+		return location, 0
+	}
+	line, err := strconv.Atoi(location[i+1:])
+	if err != nil {
+		return location, 0
+	}
+	return location[:i], line
+}
+
+func trimArguments(function string) string {
+	if !strings.HasSuffix(function, ")") {
+		return function
+	}
+	depth := 0
+	for i := len(function) - 1; i >= 0; i-- {
+		switch function[i] {
+		case ')':
+			depth++
+		case '(':
+			depth--
+			if depth == 0 {
+				return function[:i]
+			}
+		}
+	}
+	return function
+}
+
+// matchesFile reports whether path ends with the "dir/file.go" name recorded by callerInfos.
+func matchesFile(path, name string) bool {
+	path = filepath.ToSlash(path)
+	return path == name || strings.HasSuffix(path, "/"+name)
+}
@@ -0,0 +1,108 @@
+package errors
+
+import (
+	"strings"
+	"testing"
+)
+
+const testStack = `goroutine 7 [running]:
+runtime/debug.Stack()
+	/usr/local/go/src/runtime/debug/stack.go:26 +0x5e
+github.com/tech4works/errors.newDetail(0x1, {0xc000012345, 0x4})
+	/root/module/detail.go:310 +0x3d
+github.com/org/app/users.(*Service).Find(0xc00001, {0x0?, 0x1})
+	/src/app/users/service.go:42 +0x25
+main.main()
+	/src/app/main.go:10 +0x1d
+created by main.start in goroutine 1
+	/src/app/main.go:5 +0x66
+`
+
+func TestParseStack(t *testing.T) {
+	frames := ParseStack(testStack)
+	want := []Frame{
+		{"runtime/debug.Stack", "/usr/local/go/src/runtime/debug/stack.go", 26},
+		{"github.com/tech4works/errors.newDetail", "/root/module/detail.go", 310},
+		{"github.com/org/app/users.(*Service).Find", "/src/app/users/service.go", 42},
+		{"main.main", "/src/app/main.go", 10},
+	}
+	if len(frames) != len(want) {
+		t.Fatalf("ParseStack() = %v, want %v", frames, want)
+	}
+	for i := range want {
+		if frames[i] != want[i] {
+			t.Errorf("ParseStack()[%d] = %v, want %v", i, frames[i], want[i])
+		}
+	}
+}
+
+func TestFrame_Package(t *testing.T) {
+	tests := []struct {
+		function  string
+		wantPkg   string
+		wantShort string
+	}{
+		{"github.com/org/app/users.(*Service).Find", "github.com/org/app/users", "(*Service).Find"},
+		{"main.main", "main", "main"},
+		{"github.com/org/app.Run.func1", "github.com/org/app", "Run.func1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			frame := Frame{Function: tt.function}
+			if frame.Package() != tt.wantPkg || frame.ShortFunction() != tt.wantShort {
+				t.Errorf("Frame.Package() = %v, Frame.ShortFunction() = %v", frame.Package(), frame.ShortFunction())
+			}
+		})
+	}
+}
+
+func TestDetail_Frames(t *testing.T) {
+	frames := Details(New("failed")).Frames()
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, ".TestDetail_Frames") {
+		t.Errorf("Detail.Frames() should start at the caller of New, got %v", frames)
+	}
+
+	e := &Detail{file: "users/service.go", line: "42", stack: testStack}
+	if frames = e.Frames(); len(frames) != 2 || frames[0].Function != "github.com/org/app/users.(*Service).Find" {
+		t.Errorf("Detail.Frames() = %v", frames)
+	}
+}
+
+func TestFrame_IsStandard(t *testing.T) {
+	tests := []struct {
+		function string
+		want     bool
+	}{
+		{"github.com/org/app/service.(*Service).Get", false},
+		{"main.main", false},
+		{"net/http.(*conn).serve", true},
+		{"runtime.goexit", true},
+		{"testing.tRunner", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			if got := (Frame{Function: tt.function}).IsStandard(); got != tt.want {
+				t.Errorf("Frame.IsStandard() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrame_Source(t *testing.T) {
+	frame := Details(New("test error")).Frames()[0]
+
+	lines, err := frame.Source(1)
+	if err != nil {
+		t.Fatalf("Frame.Source() error = %v", err)
+	}
+	if len(lines) != 3 || !lines[1].Current || lines[1].Number != frame.Line ||
+		!strings.Contains(lines[1].Text, `Details(New("test error"))`) {
+		t.Errorf("Frame.Source() = %+v", lines)
+	}
+
+	if _, err = (Frame{File: "missing.go", Line: 1}).Source(1); err == nil {
+		t.Error("Frame.Source() should fail for a missing file")
+	}
+}
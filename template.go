@@ -0,0 +1,160 @@
+package errors
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Fields holds structured data attached to an error, such as the values of the named placeholders of
+// a Definition template.
+type Fields map[string]any
+
+// template is a parsed message template, where placeholders are written as "{name}". Braces that do
+// not enclose a valid name, such as "{}" or "{ a }", are kept as literal text.
+type template struct {
+	parts []templatePart
+	names []string
+}
+
+type templatePart struct {
+	text        string
+	placeholder bool
+}
+
+func parseTemplate(raw string) template {
+	var t template
+	rest := raw
+	for len(rest) > 0 {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			break
+		}
+		name := rest[start+1 : start+end]
+		if !isPlaceholderName(name) {
+			t.parts = append(t.parts, templatePart{text: rest[:start+1]})
+			rest = rest[start+1:]
+			continue
+		}
+		t.parts = append(t.parts, templatePart{text: rest[:start]}, templatePart{text: name, placeholder: true})
+		if !slices.Contains(t.names, name) {
+			t.names = append(t.names, name)
+		}
+		rest = rest[start+end+1:]
+	}
+	t.parts = append(t.parts, templatePart{text: rest})
+	return t
+}
+
+// render fills the template, with the values selected by the redaction policy replaced, and returns the
+// message together with the fields recorded for it. A single Fields argument fills the placeholders by
+// name, otherwise the arguments fill the distinct placeholder names in order of appearance and the
+// remaining ones are appended to the message. A Fields argument is checked against the placeholders and,
+// like the fmt package does, the placeholders it has no value for are rendered as "%!{name}(MISSING)",
+// while its keys that match no placeholder are left out of the fields and reported as
+// "%!(EXTRA key=value)" at the end of the message.
+func (t template) render(args ...any) (string, Fields) {
+	var fields, extra Fields
+	named := false
+	if len(args) == 1 {
+		if values, ok := args[0].(Fields); ok {
+			named = true
+			fields = Fields{}
+			for k, v := range values {
+				if slices.Contains(t.names, k) {
+					fields[k] = v
+				} else {
+					if extra == nil {
+						extra = Fields{}
+					}
+					extra[k] = v
+				}
+			}
+			if len(fields) == 0 {
+				fields = nil
+			}
+			args = nil
+		}
+	}
+	if fields == nil && len(args) > 0 {
+		fields = Fields{}
+		for i, name := range t.names {
+			if i >= len(args) {
+				break
+			}
+			fields[name] = args[i]
+		}
+		args = args[min(len(args), len(t.names)):]
+	}
+
+	c := loadConfig()
+	var sb strings.Builder
+	for _, part := range t.parts {
+		value, ok := fields[part.text]
+		if !part.placeholder {
+			sb.WriteString(part.text)
+		} else if ok {
+			sb.WriteString(toString(c.redact(part.text, value)))
+		} else if named {
+			sb.WriteString("%!{" + part.text + "}(MISSING)")
+		} else {
+			sb.WriteString("{" + part.text + "}")
+		}
+	}
+	if len(extra) > 0 {
+		pairs := make([]string, 0, len(extra))
+		for _, key := range sortedKeys(extra) {
+			pairs = append(pairs, key+"="+toString(c.redact(key, extra[key])))
+		}
+		sb.WriteString(" %!(EXTRA " + strings.Join(pairs, ", ") + ")")
+	}
+
+	if len(args) > 0 {
+		return buildMessage(append([]any{sb.String()}, args...)...), fields
+	}
+	return cleanMessage(sb.String()), fields
+}
+
+// validate checks that the placeholders of the template match exactly the declared params.
+func (t template) validate(params []string) error {
+	var missing, extra []string
+	for _, name := range t.names {
+		if !slices.Contains(params, name) {
+			missing = append(missing, name)
+		}
+	}
+	for _, param := range params {
+		if !slices.Contains(t.names, param) {
+			extra = append(extra, param)
+		}
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "undeclared placeholders "+strings.Join(missing, ", "))
+	}
+	if len(extra) > 0 {
+		problems = append(problems, "unused params "+strings.Join(extra, ", "))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, " and "))
+	}
+	return nil
+}
+
+func isPlaceholderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		letter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		if !letter && (i == 0 || r < '0' || r > '9') && (i == 0 || r != '.') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTemplate_Render(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		args       []any
+		wantMsg    string
+		wantFields Fields
+	}{
+		{"Named fields", "order {orderID} exceeds limit {limit}", []any{Fields{"orderID": "A1", "limit": 10}},
+			"order A1 exceeds limit 10", Fields{"orderID": "A1", "limit": 10}},
+		{"Positional args", "order {orderID} exceeds limit {limit}", []any{"A1", 10},
+			"order A1 exceeds limit 10", Fields{"orderID": "A1", "limit": 10}},
+		{"Missing field", "order {orderID} exceeds limit {limit}", []any{Fields{"orderID": "A1"}},
+			"order A1 exceeds limit %!{limit}(MISSING)", Fields{"orderID": "A1"}},
+		{"Extra field", "order {orderID}", []any{Fields{"orderID": "A1", "limit": 10, "amount": 5}},
+			"order A1 %!(EXTRA amount=5, limit=10)", Fields{"orderID": "A1"}},
+		{"Only extra fields", "order {orderID}", []any{Fields{"limit": 10}},
+			"order %!{orderID}(MISSING) %!(EXTRA limit=10)", nil},
+		{"Missing positional arg", "order {orderID} exceeds limit {limit}", []any{"A1"},
+			"order A1 exceeds limit {limit}", Fields{"orderID": "A1"}},
+		{"Repeated placeholder", "{id} and {id}", []any{1}, "1 and 1", Fields{"id": 1}},
+		{"Literal braces", "payload {} was { invalid }", nil, "payload {} was { invalid }", nil},
+		{"Extra args", "user {id}", []any{1, "extra"}, "user 1 extra", Fields{"id": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, fields := parseTemplate(tt.template).render(tt.args...)
+			if msg != tt.wantMsg {
+				t.Errorf("template.render() message = %v, want %v", msg, tt.wantMsg)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("template.render() fields = %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestTemplate_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		params   []string
+		wantErr  bool
+	}{
+		{"Params match", "order {orderID} exceeds limit {limit}", []string{"limit", "orderID"}, false},
+		{"Param missing", "order {orderID} exceeds limit {limit}", []string{"orderID"}, true},
+		{"Param extra", "order {orderID}", []string{"orderID", "limit"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := parseTemplate(tt.template).validate(tt.params); (err != nil) != tt.wantErr {
+				t.Errorf("template.validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefineWithParams(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Define() should panic when params do not match the template")
+		}
+	}()
+	Define("TEST_TEMPLATE_INVALID_PARAMS", Internal, "order {orderID}", Params("orderID", "limit"))
+}
+
+func TestDetail_Fields(t *testing.T) {
+	def := Define("TEST_TEMPLATE_FIELDS", FailedPrecondition, "order {orderID} exceeds limit {limit}",
+		Params("orderID", "limit"))
+
+	detail := Details(def.New(Fields{"orderID": "A1", "limit": 10}))
+	if detail.Message() != "order A1 exceeds limit 10" {
+		t.Errorf("Detail.Message() = %v", detail.Message())
+	}
+	if want := (Fields{"orderID": "A1", "limit": 10}); !reflect.DeepEqual(detail.Fields(), want) {
+		t.Errorf("Detail.Fields() = %v, want %v", detail.Fields(), want)
+	}
+	if fields := Details(New("no fields")).Fields(); fields != nil {
+		t.Errorf("Detail.Fields() = %v, want nil", fields)
+	}
+}
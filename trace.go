@@ -0,0 +1,127 @@
+package errors
+
+import (
+	"runtime"
+	"slices"
+	"time"
+)
+
+// Trace appends the location of its caller to the return trace of err, recording that the error was
+// returned through it. Inspired by the error return traces of Zig, the return trace shows the path the
+// error took back to the caller that handled it, which the creation stack alone does not show when the
+// error is created in a shared helper. An error that is not a *Detail is wrapped into one, which keeps
+// the original error in its chain.
+//
+// Parameters:
+//   - err: The error being returned.
+//
+// Returns:
+//   - error: A *Detail with the caller appended to its return trace, or nil when err is nil.
+//
+// Example:
+//
+//	func LoadConfig(path string) (*Config, error) {
+//		bs, err := readFile(path)
+//		if err != nil {
+//			return nil, errors.Trace(err)
+//		}
+//		// ...
+//	}
+func Trace(err error) error {
+	if err == nil {
+		return nil
+	}
+	detail, ok := err.(*Detail)
+	if !ok {
+		return wrapTrace(1, err)
+	}
+	clone := *detail
+	clone.appendTrace(1)
+	return &clone
+}
+
+// ReturnTrace returns the path err was returned through, from the point where it was created to the
+// outermost point it reached: the origin of every *Detail of its chain, which includes the points where
+// it was wrapped, followed by the locations appended by Trace and Op. Only the first error wrapped by an
+// error that wraps multiple errors, such as the ones returned by the standard library errors.Join, is
+// followed.
+//
+// Parameters:
+//   - err: The error to be inspected.
+//
+// Returns:
+//   - []Frame: The frames of the return trace, or nil when err has no *Detail.
+//
+// Example:
+//
+//	for _, frame := range errors.ReturnTrace(err) {
+//		fmt.Printf("%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+//	}
+func ReturnTrace(err error) []Frame {
+	var chain []*Detail
+	for err != nil {
+		if detail, ok := err.(*Detail); ok {
+			chain = append(chain, detail)
+		}
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		case interface{ Unwrap() []error }:
+			causes := x.Unwrap()
+			err = nil
+			if len(causes) > 0 {
+				err = causes[0]
+			}
+		default:
+			err = nil
+		}
+	}
+
+	var frames []Frame
+	for i := len(chain) - 1; i >= 0; i-- {
+		frames = append(frames, chain[i].origin())
+		frames = append(frames, chain[i].trace...)
+	}
+	return frames
+}
+
+// origin returns the frame where the error was created, from its stack when possible, so the function
+// and file are fully qualified.
+func (e *Detail) origin() Frame {
+	if frames := e.Frames(); len(frames) > 0 && frames[0].Line == e.Line() {
+		return frames[0]
+	}
+	return Frame{Function: e.funcName, File: e.file, Line: e.Line()}
+}
+
+// wrapTrace wraps an error that is not a *Detail into a minimal one for Trace and Op, which records only
+// the caller frame identified by skip, following the newDetail convention, as its origin. Unlike wrap,
+// it captures no stack nor metadata, since the error was created elsewhere and only passes through.
+func wrapTrace(skip int, err error) *Detail {
+	file, line, funcName := callerInfos(skip + 2)
+	pcs := make([]uintptr, 1)
+	detail := &Detail{
+		file:     file,
+		line:     line,
+		funcName: funcName,
+		cause:    err,
+		pcs:      pcs[:runtime.Callers(skip+2, pcs)],
+		time:     time.Now(),
+	}
+	detail.inherit(err)
+	return detail
+}
+
+// appendTrace appends the caller frame identified by skip, following the newDetail convention, to the
+// return trace of the error, which must be a copy not shared with other errors.
+func (e *Detail) appendTrace(skip int) {
+	pc, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return
+	}
+	frame := Frame{File: file, Line: line}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		frame.Function = fn.Name()
+	}
+	e.trace = append(slices.Clip(e.trace), frame)
+}
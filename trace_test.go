@@ -0,0 +1,132 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func traceLoad(fail error) error {
+	return fail
+}
+
+func traceRepository(fail error) error {
+	if err := traceLoad(fail); err != nil {
+		return Trace(err)
+	}
+	return nil
+}
+
+func traceService(fail error) (err error) {
+	defer Op("service.find", &err)()
+	if err = traceRepository(fail); err != nil {
+		return Wrap(err, "finding user")
+	}
+	return nil
+}
+
+func TestTrace(t *testing.T) {
+	tests := []struct {
+		name string
+		fail error
+		want []string
+	}{
+		{
+			"Detailed error",
+			New("not found"),
+			[]string{"TestTrace", "traceRepository", "traceService", "traceService"},
+		},
+		{
+			"Standard error",
+			io.EOF,
+			[]string{"traceRepository", "traceService", "traceService"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := traceService(tt.fail)
+
+			trace := ReturnTrace(err)
+			var got []string
+			for _, frame := range trace {
+				name := frame.ShortFunction()
+				if i := strings.IndexByte(name, '.'); i >= 0 {
+					name = name[:i]
+				}
+				got = append(got, name)
+				if !strings.HasSuffix(frame.File, "trace_test.go") || frame.Line == 0 {
+					t.Errorf("ReturnTrace() frame = %+v", frame)
+				}
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Fatalf("ReturnTrace() = %v, want %v", got, tt.want)
+			}
+			if _, ok := tt.fail.(*Detail); !ok && !errors.Is(err, tt.fail) {
+				t.Errorf("Trace() lost the original error %v", tt.fail)
+			}
+			if len(ReturnTrace(tt.fail)) > 1 {
+				t.Errorf("Trace() changed the original error")
+			}
+		})
+	}
+}
+
+func TestTraceStandardError(t *testing.T) {
+	errs := []error{Trace(io.EOF)}
+	err := io.ErrUnexpectedEOF
+	func() {
+		defer Op("stream.read", &err)()
+	}()
+	errs = append(errs, err)
+
+	for _, err := range errs {
+		detail := err.(*Detail)
+		if detail.Stack() != "" {
+			t.Errorf("%v captured a stack: %s", err, detail.Stack())
+		}
+		if _, ok := detail.Metadata(); ok {
+			t.Errorf("%v captured metadata", err)
+		}
+		if frames := detail.Frames(); len(frames) != 1 || !strings.Contains(frames[0].Function, "TestTraceStandardError") {
+			t.Errorf("%v frames = %+v, want the caller only", err, frames)
+		}
+		if trace := ReturnTrace(err); len(trace) != 1 || trace[0].Line != detail.Line() {
+			t.Errorf("ReturnTrace(%v) = %+v", err, trace)
+		}
+	}
+}
+
+func TestTraceNil(t *testing.T) {
+	if err := Trace(nil); err != nil {
+		t.Errorf("Trace(nil) = %v, want nil", err)
+	}
+	if trace := ReturnTrace(io.EOF); trace != nil {
+		t.Errorf("ReturnTrace() = %v, want nil", trace)
+	}
+}
+
+func TestTraceJSON(t *testing.T) {
+	err := traceService(New("not found"))
+
+	bs, marshalErr := json.Marshal(err)
+	if marshalErr != nil {
+		t.Fatal(marshalErr)
+	}
+	var decoded Detail
+	if unmarshalErr := json.Unmarshal(bs, &decoded); unmarshalErr != nil {
+		t.Fatal(unmarshalErr)
+	}
+
+	want, got := ReturnTrace(err), ReturnTrace(&decoded)
+	if len(got) != len(want) {
+		t.Fatalf("ReturnTrace() of the decoded error = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ReturnTrace()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
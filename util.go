@@ -5,10 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"path"
-	"path/filepath"
 	"reflect"
 	"regexp"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -18,16 +18,14 @@ func callerInfos(skip int) (fileName string, line string, funcName string) {
 	if !ok {
 		pc, file, lineNo, _ = runtime.Caller(1)
 	}
-	funcInfo := runtime.FuncForPC(pc).Name()
-	dir, fileBase := filepath.Split(file)
-	dirBase := filepath.Base(dir)
-	name := formatFuncName(funcInfo)
+	c := loadConfig()
+	name := c.formatFunc(runtime.FuncForPC(pc).Name())
 
 	if lineNo < 1 {
 		lineNo = 1
 	}
 
-	return dirBase + "/" + fileBase, strconv.Itoa(lineNo), name
+	return c.formatFile(file), strconv.Itoa(lineNo), name
 }
 
 func buildMessage(v ...any) string {
@@ -55,7 +53,7 @@ func filterMsg(v ...any) []any {
 		ivError, ok := iv.(error)
 		if ok {
 			errDetail := Details(ivError)
-			v[i] = errDetail.message
+			v[i] = errDetail.Message()
 		}
 	}
 	return v
@@ -67,6 +65,15 @@ func formatFuncName(name string) string {
 	return split[len(split)-1]
 }
 
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func toString(a any) string {
 	s, err := toStringWithErr(a)
 	if err != nil {
@@ -0,0 +1,213 @@
+package errors
+
+import (
+	"errors"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Wrap annotates err with a message built from args, capturing the caller information and the debug
+// stack of the wrap point. The returned *Detail keeps err as its cause, available through errors.Unwrap,
+// and its message is the wrap message followed by the message of the cause, e.g. "loading profile: user
+// 42 not found". The code, kind, fields, hints and documentation URL of the nearest *Detail in the chain
+// of err are inherited, so the wrapped error keeps its classification.
+//
+// Parameters:
+//   - err: The error to be wrapped.
+//   - args: Variadic arguments of any type to be composed into the wrap message.
+//
+// Returns:
+//   - error: A *Detail wrapping err, or nil when err is nil.
+//
+// Example:
+//
+//	err := Wrap(ErrUserNotFound.New(42), "loading profile")
+//	fmt.Println(Details(err).Message())     // loading profile: user 42 not found
+//	fmt.Println(ErrUserNotFound.Is(err))     // true
+//	fmt.Println(errors.Unwrap(err) != nil)   // true
+func Wrap(err error, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return wrap(1, err, buildMessage(args...))
+}
+
+// Wrapf works like Wrap, but the wrap message is built from a format string and its arguments.
+//
+// Parameters:
+//   - err: The error to be wrapped.
+//   - format: A format as a string.
+//   - args: Variadic arguments of any type to be composed into the wrap message following the format.
+//
+// Returns:
+//   - error: A *Detail wrapping err, or nil when err is nil.
+func Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return wrap(1, err, buildMessageByFormat(format, args...))
+}
+
+// WrapSkipCaller works like Wrap, but the caller information and the stack of the wrap point skip the
+// given number of stack frames, following the NewSkipCaller convention, where 1 identifies the caller of
+// WrapSkipCaller. It is meant for helpers that wrap errors on behalf of their callers.
+//
+// Parameters:
+//   - skipCaller: The number of stack frames to skip.
+//   - err: The error to be wrapped.
+//   - args: Variadic arguments of any type to be composed into the wrap message.
+//
+// Returns:
+//   - error: A *Detail wrapping err, or nil when err is nil.
+//
+// Example:
+//
+//	func check(err error) error {
+//		return errors.WrapSkipCaller(2, err, "checking") // reports the caller of check
+//	}
+func WrapSkipCaller(skipCaller int, err error, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return wrap(skipCaller, err, buildMessage(args...))
+}
+
+// Unwrap returns the error wrapped by the Detail instance, or nil when the Detail is not wrapping
+// another error. It allows the Detail to be used with errors.Is, errors.As and errors.Unwrap.
+//
+// Returns:
+//   - error: The wrapped error.
+func (e *Detail) Unwrap() error {
+	return e.cause
+}
+
+// wrap builds the *Detail returned by Wrap. The skip value follows the newDetail convention.
+func wrap(skip int, err error, msg string) *Detail {
+	detail := newDetail(skip+1, msg)
+	detail.cause = err
+	detail.inherit(err)
+	return detail
+}
+
+// inherit copies the classification of the outermost *Detail of the chain of err, if any, to the error.
+func (e *Detail) inherit(err error) {
+	var cause *Detail
+	if errors.As(err, &cause) {
+		e.code = cause.code
+		e.kind = cause.kind
+		e.fields = maps.Clone(cause.fields)
+		e.contextFields = cause.contextFields
+		e.hints = slices.Clip(cause.hints)
+		e.docsURL = cause.docsURL
+	}
+}
+
+// Walk calls fn for err and every error wrapped by it, in depth-first order, following both the
+// Unwrap() error and the Unwrap() []error methods, with the depth of each error in the chain, where err
+// has depth 0. It stops and returns true as soon as fn returns true.
+//
+// Parameters:
+//   - err: The error whose chain is walked.
+//   - fn: The function called for every error of the chain.
+//
+// Returns:
+//   - bool: A boolean value indicating whether fn stopped the walk.
+//
+// Example:
+//
+//	errors.Walk(err, func(err error, depth int) bool {
+//		fmt.Println(strings.Repeat("  ", depth) + errors.MessageOf(err))
+//		return false
+//	})
+func Walk(err error, fn func(err error, depth int) bool) bool {
+	return walkDepth(err, 0, fn)
+}
+
+// Origin returns the innermost *Detail of the chain of err, the last one found by Walk, which holds the
+// stack where the error was created. When the chain has no *Detail, it returns Details(err).
+//
+// Parameters:
+//   - err: The error whose origin is returned.
+//
+// Returns:
+//   - *Detail: The innermost *Detail of the chain, or nil when err is nil.
+func Origin(err error) *Detail {
+	origin := Details(err)
+	walk(err, func(err error) bool {
+		if d, ok := err.(*Detail); ok {
+			origin = d
+		}
+		return false
+	})
+	return origin
+}
+
+// walk calls fn for err and every error wrapped by it, like Walk, without the depth.
+func walk(err error, fn func(err error) bool) bool {
+	return Walk(err, func(err error, _ int) bool {
+		return fn(err)
+	})
+}
+
+func walkDepth(err error, depth int, fn func(err error, depth int) bool) bool {
+	if err == nil {
+		return false
+	}
+	if fn(err, depth) {
+		return true
+	}
+
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		return walkDepth(x.Unwrap(), depth+1, fn)
+	case interface{ Unwrap() []error }:
+		for _, e := range x.Unwrap() {
+			if walkDepth(e, depth+1, fn) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MessageOf returns the message of err without the origin and the debug stack added by Detail.Error,
+// including when the detailed error string is embedded in the message of another error, such as one
+// created by fmt.Errorf with the "%w" verb or joined by the standard library's errors.Join.
+//
+// Parameters:
+//   - err: The error whose message is returned.
+//
+// Returns:
+//   - string: The message of err.
+//
+// Example:
+//
+//	err := fmt.Errorf("loading profile: %w", errors.New("user not found"))
+//	fmt.Println(errors.MessageOf(err)) // loading profile: user not found
+func MessageOf(err error) string {
+	if detail, ok := err.(*Detail); ok {
+		return detail.Message()
+	}
+	msg := err.Error()
+	var causes []error
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		causes = []error{e.Unwrap()}
+	case interface{ Unwrap() []error }:
+		causes = e.Unwrap()
+	}
+	for _, cause := range causes {
+		if cause != nil {
+			msg = strings.Replace(msg, cause.Error(), MessageOf(cause), 1)
+		}
+	}
+	if i := strings.Index(msg, " [STACK]:"); i >= 0 && IsDetailed(err) {
+		msg = originRegex.ReplaceAllString(msg[:i], "")
+	}
+	return cleanMessage(msg)
+}
+
+// originRegex matches the origin that Detail.Error writes before the message.
+var originRegex = regexp.MustCompile(`\[CAUSE]: \([^:]+:\d+\) [^:]+: `)
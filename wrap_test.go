@@ -0,0 +1,130 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var errTestWrapNotFound = Define("TEST_WRAP_NOT_FOUND", NotFound, "user {id} not found", Hint("check the id"))
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		args        []any
+		wantMessage string
+		wantCode    string
+	}{
+		{"Wrap detailed error", errTestWrapNotFound.New(42), []any{"loading profile"}, "loading profile: user 42 not found", "TEST_WRAP_NOT_FOUND"},
+		{"Wrap standard error", errors.New("connection refused"), []any{"calling", "billing"}, "calling billing: connection refused", ""},
+		{"Wrap without message", errors.New("connection refused"), nil, "connection refused", ""},
+		{"Wrap twice", Wrap(errTestWrapNotFound.New(1), "loading profile"), []any{"handling request"}, "handling request: loading profile: user 1 not found", "TEST_WRAP_NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Wrap(tt.err, tt.args...)
+			detail := Details(err)
+			if detail.Message() != tt.wantMessage {
+				t.Errorf("Wrap() message = %v, want %v", detail.Message(), tt.wantMessage)
+			}
+			if detail.Code() != tt.wantCode {
+				t.Errorf("Wrap() code = %v, want %v", detail.Code(), tt.wantCode)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Error("Wrap() should keep the wrapped error in the chain")
+			}
+			if detail.Func() != "func1" {
+				t.Errorf("Wrap() func = %v, want func1", detail.Func())
+			}
+		})
+	}
+}
+
+func TestWrapNil(t *testing.T) {
+	if Wrap(nil, "message") != nil || Wrapf(nil, "%s", "message") != nil {
+		t.Error("Wrap() and Wrapf() should return nil for nil errors")
+	}
+}
+
+func TestWrapf(t *testing.T) {
+	err := Wrapf(errors.New("timeout"), "calling %s", "billing")
+	if got := Details(err).Message(); got != "calling billing: timeout" {
+		t.Errorf("Wrapf() message = %v", got)
+	}
+}
+
+func wrapOnBehalf(err error) error {
+	return WrapSkipCaller(2, err, "checking")
+}
+
+func TestWrapSkipCaller(t *testing.T) {
+	cause := errors.New("timeout")
+	err := wrapOnBehalf(cause)
+	if detail := Details(err); detail.Func() != "TestWrapSkipCaller" || detail.Message() != "checking: timeout" {
+		t.Errorf("WrapSkipCaller() = %v %v, want the caller of wrapOnBehalf", detail.Func(), detail.Message())
+	}
+	if !errors.Is(err, cause) {
+		t.Error("WrapSkipCaller() should keep the wrapped error in the chain")
+	}
+	if WrapSkipCaller(1, nil) != nil {
+		t.Error("WrapSkipCaller() should return nil for nil errors")
+	}
+}
+
+func TestWrapKeepsClassification(t *testing.T) {
+	err := Wrap(errTestWrapNotFound.New(42), "loading profile")
+	detail := Details(err)
+
+	if !errTestWrapNotFound.Is(err) || detail.Kind() != NotFound {
+		t.Errorf("Wrap() should keep the code and kind, got %v %v", detail.Code(), detail.Kind())
+	}
+	if len(detail.Hints()) != 1 || detail.Fields()["id"] != 42 {
+		t.Errorf("Wrap() should keep the hints and fields, got %v %v", detail.Hints(), detail.Fields())
+	}
+	if !IsDetailed(err) || Details(errors.New(err.Error())).Message() != detail.Message() {
+		t.Error("Wrap() error string should be parsed back to the same message")
+	}
+}
+
+func TestMessageOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"Detail", errTestWrapNotFound.New(42), "user 42 not found"},
+		{"Standard error", errors.New("timeout"), "timeout"},
+		{"Wrapped by fmt", fmt.Errorf("loading profile: %w", errTestWrapNotFound.New(42)), "loading profile: user 42 not found"},
+		{"Joined", errors.Join(errTestWrapNotFound.New(1), errors.New("timeout")), "user 1 not found timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MessageOf(tt.err); got != tt.want {
+				t.Errorf("MessageOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalkAndOrigin(t *testing.T) {
+	inner := errTestWrapNotFound.New(1)
+	err := Wrap(errors.Join(errors.New("timeout"), fmt.Errorf("retrying: %w", inner)), "loading profile")
+
+	var depths []int
+	Walk(err, func(_ error, depth int) bool {
+		depths = append(depths, depth)
+		return false
+	})
+	if fmt.Sprint(depths) != "[0 1 2 2 3]" {
+		t.Errorf("Walk() depths = %v, want [0 1 2 2 3]", depths)
+	}
+	if Origin(err) != inner {
+		t.Errorf("Origin() = %v, want the innermost detail", Origin(err))
+	}
+	if Origin(nil) != nil {
+		t.Error("Origin() should return nil for nil errors")
+	}
+}